@@ -10,6 +10,7 @@ import (
 	"article-dispatcher/internal/http/responses"
 	"article-dispatcher/internal/pkg/log"
 	"article-dispatcher/internal/pkg/metrics"
+	"article-dispatcher/internal/pkg/tracing"
 	servicesImp "article-dispatcher/internal/services"
 	"os"
 	"os/signal"
@@ -46,12 +47,16 @@ func TestE2E(t *testing.T) {
 	cache := cacheImp.NewCache(l)
 
 	// article service implement
-	articleService := servicesImp.NewArticleService(l, cache)
+	articleService := servicesImp.NewArticleService(l, cache, tracing.NoopTracer{})
 
 	// init router
 	port := http.Config.Host
 	router := http.Router{Conf: &http.Config}
-	router.Init(l, articleService, metrics.RequestLatency)
+	err = router.Init(l, articleService, metrics.RequestLatency)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
 
 	go func() {
 		err := router.Start()
@@ -167,11 +172,12 @@ func runArticleGetRequest(id, port string, t *testing.T) {
 
 	// expected response
 	expectedArticle := models.Article{
-		Id:    "1",
-		Title: "test",
-		Date:  "2023-03-30",
-		Body:  "test body",
-		Tags:  []string{"nature", "health"},
+		Id:      "1",
+		Title:   "test",
+		Date:    "2023-03-30",
+		Body:    "test body",
+		Tags:    []string{"nature", "health"},
+		Version: 1,
 	}
 
 	client := netHttp.Client{}
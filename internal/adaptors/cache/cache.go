@@ -10,6 +10,7 @@ import (
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
 var articleIDCount int64
@@ -21,30 +22,139 @@ type cache struct {
 	lock         *sync.RWMutex
 	articles     map[string]models.Article
 	tagDateIndex map[string][]string
+	// tagDateModified last modification of each tag-date index, kept when the index is emptied so that it only
+	// moves forward
+	tagDateModified map[string]time.Time
+	// sourceIndex unique index of the article provenance to the article id
+	sourceIndex map[provenance]string
+}
+
+// provenance source system and the external id of an imported article
+type provenance struct {
+	source     string
+	externalID string
 }
 
 func NewCache(l logger.Logger) repository.Repository {
 	return &cache{
-		log:          l,
-		lock:         &sync.RWMutex{},
-		articles:     make(map[string]models.Article),
-		tagDateIndex: make(map[string][]string),
+		log:             l,
+		lock:            &sync.RWMutex{},
+		articles:        make(map[string]models.Article),
+		tagDateIndex:    make(map[string][]string),
+		tagDateModified: make(map[string]time.Time),
+		sourceIndex:     make(map[provenance]string),
 	}
 }
 
-// Set article data into the cache
+// Set article data into the cache with a newly generated id
 func (c cache) Set(_ context.Context, article *models.Article) error {
 	c.lock.Lock()
 	defer c.lock.Unlock()
+	date, err := articleDate(article)
+	if err != nil {
+		return err
+	}
+	// created articles always get a new id, so any article with the same provenance is a conflict
+	article.Id = ""
+	if err = c.checkSource(article); err != nil {
+		return err
+	}
+
+	// skip the ids already chosen by the clients
 	id := fmt.Sprintf("%d", atomic.AddInt64(&articleIDCount, 1))
+	for _, ok := c.articles[id]; ok; _, ok = c.articles[id] {
+		id = fmt.Sprintf("%d", atomic.AddInt64(&articleIDCount, 1))
+	}
 	article.Id = id
+	article.Version = 1
+
+	c.index(article, date)
+
+	return nil
+}
 
-	c.articles[id] = *article
+// Put article data into the cache with its own id, replacing the existing article with the same id,
+// the precondition is checked against the stored article under the write lock
+func (c cache) Put(_ context.Context, article *models.Article, precondition models.Precondition) (bool, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	date, err := articleDate(article)
+	if err != nil {
+		return false, err
+	}
+
+	existing, exists := c.articles[article.Id]
+	if err = checkPrecondition(article.Id, existing, exists, precondition); err != nil {
+		return false, err
+	}
+	if err = c.checkSource(article); err != nil {
+		return false, err
+	}
+
+	article.Version = 1
+	if exists {
+		article.Version = existing.Version + 1
+		c.unindex(&existing)
+	}
+	c.index(article, date)
+
+	return !exists, nil
+}
+
+// checkPrecondition - check the write precondition against the stored article, caller must hold the lock
+func checkPrecondition(id string, existing models.Article, exists bool, precondition models.Precondition) error {
+	if exists && precondition.CreateOnly {
+		err := fmt.Errorf("error, article already exists with id [%s]", id)
+		return AlreadyExistsError{err}
+	}
+	if !exists && (precondition.MustExist || len(precondition.MatchVersions) > 0) {
+		err := fmt.Errorf("error, no article found with id [%s] to replace", id)
+		return VersionMismatchError{err}
+	}
+	if !exists || len(precondition.MatchVersions) == 0 {
+		return nil
+	}
+
+	for _, version := range precondition.MatchVersions {
+		if version == existing.Version {
+			return nil
+		}
+	}
+	err := fmt.Errorf("error, article [%s] is at version [%d] not at the expected versions %v",
+		id, existing.Version, precondition.MatchVersions)
+	return VersionMismatchError{err}
+}
+
+// articleDate - convert the article date into the index date format `yyyymmdd`
+func articleDate(article *models.Article) (int, error) {
 	date, err := strconv.Atoi(strings.ReplaceAll(article.Date, "-", ""))
 	if err != nil {
 		err := fmt.Errorf("error, invalid date format [%s] expected yyyymmdd ", article.Date)
-		return InvalidDataError{err}
+		return 0, InvalidDataError{err}
 	}
+	return date, nil
+}
+
+// checkSource - check the article provenance is not already taken by another article,
+// caller must hold the lock
+func (c cache) checkSource(article *models.Article) error {
+	if article.Source == "" {
+		return nil
+	}
+	id, ok := c.sourceIndex[sourceKey(article)]
+	if ok && id != article.Id {
+		err := fmt.Errorf("error, article [%s] already exists with source [%s] external id [%s]",
+			id, article.Source, article.ExternalID)
+		return ConflictError{err}
+	}
+	return nil
+}
+
+// index - store the article and update the tag-date and source indexes, caller must hold the lock
+func (c cache) index(article *models.Article, date int) {
+	article.StoredAt = time.Now().UTC()
+	c.articles[article.Id] = *article
+
 	// update tag-date index cache
 	for _, tag := range article.Tags {
 		tagDate := fmt.Sprintf("%s#%d", tag, date)
@@ -53,9 +163,50 @@ func (c cache) Set(_ context.Context, article *models.Article) error {
 			c.tagDateIndex[tagDate] = make([]string, 0)
 		}
 		c.tagDateIndex[tagDate] = append(c.tagDateIndex[tagDate], article.Id)
+		c.touch(tagDate, article.StoredAt)
 	}
 
-	return nil
+	if article.Source != "" {
+		c.sourceIndex[sourceKey(article)] = article.Id
+	}
+}
+
+// unindex - remove the article from the tag-date and source indexes, caller must hold the lock
+func (c cache) unindex(article *models.Article) {
+	// stored articles always have a valid date
+	date, _ := articleDate(article)
+	for _, tag := range article.Tags {
+		tagDate := fmt.Sprintf("%s#%d", tag, date)
+		c.touch(tagDate, time.Now().UTC())
+		ids := make([]string, 0, len(c.tagDateIndex[tagDate]))
+		for _, id := range c.tagDateIndex[tagDate] {
+			if id != article.Id {
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) == 0 {
+			delete(c.tagDateIndex, tagDate)
+			continue
+		}
+		c.tagDateIndex[tagDate] = ids
+	}
+
+	if article.Source != "" {
+		delete(c.sourceIndex, sourceKey(article))
+	}
+	delete(c.articles, article.Id)
+}
+
+// touch - move the last modification of the tag-date index forward to the time, caller must hold the lock
+func (c cache) touch(tagDate string, t time.Time) {
+	if t.After(c.tagDateModified[tagDate]) {
+		c.tagDateModified[tagDate] = t
+	}
+}
+
+// sourceKey - source index key of the article provenance
+func sourceKey(article *models.Article) provenance {
+	return provenance{source: article.Source, externalID: article.ExternalID}
 }
 
 // Get article data from the cache
@@ -71,12 +222,65 @@ func (c cache) Get(_ context.Context, id string) (models.Article, error) {
 	return article, nil
 }
 
+// GetMany articles data from the cache under a single read lock, ids not found are reported as missing
+func (c cache) GetMany(_ context.Context, ids []string) (models.BatchArticles, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	batch := models.BatchArticles{
+		Articles: make(models.Articles, 0, len(ids)),
+		Missing:  make([]string, 0),
+	}
+	for _, id := range ids {
+		article, ok := c.articles[id]
+		if !ok {
+			batch.Missing = append(batch.Missing, id)
+			continue
+		}
+		batch.Articles = append(batch.Articles, article)
+	}
+
+	return batch, nil
+}
+
 // Filter get list of articles satisfying with the filter options
 func (c cache) Filter(_ context.Context, tag string, date int) (models.TaggedArticles, error) {
 	c.lock.RLock()
 	defer c.lock.RUnlock()
-	// temporary tags map to get the counts and avoid deduplication
+
+	return c.filter(tag, date)
+}
+
+// FilterExpanded get list of articles satisfying with the filter options along with the full articles,
+// ids and articles are read under the same lock to return a consistent view
+func (c cache) FilterExpanded(_ context.Context, tag string, date int) (models.ExpandedTaggedArticles, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	expandedArticles := models.ExpandedTaggedArticles{
+		Articles:    make(models.Articles, 0),
+		RelatedTags: make([]string, 0),
+	}
+	taggedArticles, err := c.filter(tag, date)
+	if err != nil {
+		return expandedArticles, err
+	}
+
+	for _, id := range taggedArticles.Articles {
+		expandedArticles.Articles = append(expandedArticles.Articles, c.articles[id])
+	}
+	expandedArticles.Tag = taggedArticles.Tag
+	expandedArticles.Count = taggedArticles.Count
+	expandedArticles.RelatedTags = taggedArticles.RelatedTags
+	expandedArticles.LastModified = taggedArticles.LastModified
+
+	return expandedArticles, nil
+}
+
+// filter get the tagged articles for the tag and date, caller must hold the read lock
+func (c cache) filter(tag string, date int) (models.TaggedArticles, error) {
+	// temporary tags map to get the counts and avoid duplication, related tags keep the first seen order
 	tagsMap := make(map[string]struct{})
+	relatedTags := make([]string, 0)
 
 	// initialize maps
 	taggedArticles := models.TaggedArticles{
@@ -94,6 +298,9 @@ func (c cache) Filter(_ context.Context, tag string, date int) (models.TaggedArt
 
 	// get the articles one by one from the articleIDs returned from the indexMap and add the tags into the temporary
 	// map defined earlier
+	// articles moved out of the index modify it too, so the time is tracked per index rather than taken from
+	// the articles
+	taggedArticles.LastModified = c.tagDateModified[tagDateKey]
 	for _, id := range articleIDs {
 		article := c.articles[id]
 		// write into maps to avoid duplications
@@ -102,7 +309,11 @@ func (c cache) Filter(_ context.Context, tag string, date int) (models.TaggedArt
 			if t == tag {
 				continue
 			}
+			if _, ok := tagsMap[t]; ok {
+				continue
+			}
 			tagsMap[t] = struct{}{}
+			relatedTags = append(relatedTags, t)
 		}
 	}
 
@@ -115,8 +326,6 @@ func (c cache) Filter(_ context.Context, tag string, date int) (models.TaggedArt
 		latestArticleIDs = articleIDs[limit:]
 	}
 
-	// returns only the values of the map into a string slice
-	relatedTags := getValueSlice(tagsMap)
 	taggedArticles.RelatedTags = relatedTags
 	taggedArticles.Articles = latestArticleIDs
 	// added one since filtered tag was removed initially from map
@@ -125,12 +334,3 @@ func (c cache) Filter(_ context.Context, tag string, date int) (models.TaggedArt
 
 	return taggedArticles, nil
 }
-
-// getValueSlice - get keys of a map as a slice of strings
-func getValueSlice(inputMap map[string]struct{}) (outKeySlice []string) {
-	outKeySlice = make([]string, 0)
-	for key := range inputMap {
-		outKeySlice = append(outKeySlice, key)
-	}
-	return
-}
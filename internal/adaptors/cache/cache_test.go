@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"sync"
 	"testing"
+	"time"
 )
 
 func TestCache_Set(t *testing.T) {
@@ -98,10 +99,11 @@ func TestCache_Set(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			c := cache{
-				log:          tt.fields.log,
-				lock:         tt.fields.lock,
-				articles:     tt.fields.articles,
-				tagDateIndex: tt.fields.tagDateIndexMap,
+				log:             tt.fields.log,
+				lock:            tt.fields.lock,
+				articles:        tt.fields.articles,
+				tagDateIndex:    tt.fields.tagDateIndexMap,
+				tagDateModified: make(map[string]time.Time),
 			}
 			if err := c.Set(tt.args.ctx, tt.args.article); (err != nil) != tt.wantErr {
 				t.Errorf("Set() error = %v, wantErr %v", err, tt.wantErr)
@@ -218,6 +220,58 @@ func TestCache_Get(t *testing.T) {
 }
 
 // nolint: funlen
+func TestCache_GetMany(t *testing.T) {
+	l, err := log.NewLogger(log.ERROR)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	first := models.Article{Id: "1", Title: "first", Date: "2023-03-30", Body: "first body", Tags: []string{"fun"}}
+	second := models.Article{Id: "2", Title: "second", Date: "2023-03-31", Body: "second body", Tags: []string{"health"}}
+	c := cache{
+		log:          l,
+		lock:         &sync.RWMutex{},
+		articles:     map[string]models.Article{first.Id: first, second.Id: second},
+		tagDateIndex: make(map[string][]string),
+	}
+
+	tests := []struct {
+		name string
+		ids  []string
+		want models.BatchArticles
+	}{
+		{
+			name: "all_found_in_requested_order",
+			ids:  []string{"2", "1"},
+			want: models.BatchArticles{Articles: models.Articles{second, first}, Missing: []string{}},
+		},
+		{
+			name: "missing_ids_reported",
+			ids:  []string{"1", "3", "4"},
+			want: models.BatchArticles{Articles: models.Articles{first}, Missing: []string{"3", "4"}},
+		},
+		{
+			name: "none_found",
+			ids:  []string{"3"},
+			want: models.BatchArticles{Articles: models.Articles{}, Missing: []string{"3"}},
+		},
+		{
+			// duplicates are removed by the handler, the cache resolves each of the requested ids
+			name: "duplicate_ids",
+			ids:  []string{"1", "1", "3", "3"},
+			want: models.BatchArticles{Articles: models.Articles{first, first}, Missing: []string{"3", "3"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.GetMany(context.Background(), tt.ids)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func TestCache_Filter(t *testing.T) {
 	type fields struct {
 		log             logger.Logger
@@ -392,3 +446,249 @@ func TestCache_Filter(t *testing.T) {
 		})
 	}
 }
+
+func TestCache_FilterExpanded(t *testing.T) {
+	l, err := log.NewLogger(log.ERROR)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	article := models.Article{
+		Id:    "1",
+		Title: "test",
+		Date:  "2023-03-30",
+		Body:  "test body",
+		Tags:  []string{"fun", "health"},
+	}
+
+	c := cache{
+		log:          l,
+		lock:         &sync.RWMutex{},
+		articles:     map[string]models.Article{article.Id: article},
+		tagDateIndex: map[string][]string{"fun#20230330": {"1"}, "health#20230330": {"1"}},
+	}
+
+	tests := []struct {
+		name    string
+		tag     string
+		date    int
+		want    models.ExpandedTaggedArticles
+		wantErr bool
+	}{
+		{
+			name: "filter_expanded_existing_article",
+			tag:  "health",
+			date: 20230330,
+			want: models.ExpandedTaggedArticles{
+				Tag:         "health",
+				Count:       2,
+				Articles:    models.Articles{article},
+				RelatedTags: []string{"fun"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "filter_expanded_non_existing_article",
+			tag:  "invalid",
+			date: 20230330,
+			want: models.ExpandedTaggedArticles{
+				Articles:    make(models.Articles, 0),
+				RelatedTags: make([]string, 0),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.FilterExpanded(context.Background(), tt.tag, tt.date)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("FilterExpanded() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !assert.Equal(t, tt.want, got) {
+				t.Errorf("FilterExpanded() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// nolint:funlen
+func TestCache_List(t *testing.T) {
+	l, err := log.NewLogger(log.ERROR)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	articles := map[string]models.Article{
+		"1":  {Id: "1", Title: "Potato chips", Date: "2023-03-30", Tags: []string{"fun", "health"}},
+		"2":  {Id: "2", Title: "Apples", Date: "2023-03-28", Tags: []string{"health"}},
+		"10": {Id: "10", Title: "More potatoes", Date: "2023-04-02", Tags: []string{"fun"}},
+	}
+	c := cache{
+		log:          l,
+		lock:         &sync.RWMutex{},
+		articles:     articles,
+		tagDateIndex: make(map[string][]string),
+	}
+
+	tests := []struct {
+		name       string
+		query      models.Query
+		wantIDs    []string
+		wantCursor string
+		wantErr    bool
+	}{
+		{
+			name:    "list_all_sorted_by_numeric_id",
+			query:   models.Query{SortBy: models.SortByID, Order: models.SortAscending},
+			wantIDs: []string{"1", "2", "10"},
+		},
+		{
+			name:    "list_sorted_by_date_descending",
+			query:   models.Query{SortBy: models.SortByDate, Order: models.SortDescending},
+			wantIDs: []string{"10", "1", "2"},
+		},
+		{
+			name:    "list_by_tags_and_title",
+			query:   models.Query{Tags: []string{"fun"}, TitleContains: "POTATO", SortBy: models.SortByTitle},
+			wantIDs: []string{"10", "1"},
+		},
+		{
+			name:    "list_by_date_range",
+			query:   models.Query{DateFrom: "2023-03-29", DateTo: "2023-03-31"},
+			wantIDs: []string{"1"},
+		},
+		{
+			name:       "list_first_page",
+			query:      models.Query{SortBy: models.SortByID, Limit: 2},
+			wantIDs:    []string{"1", "2"},
+			wantCursor: encodeCursor(2),
+		},
+		{
+			name:    "list_last_page",
+			query:   models.Query{SortBy: models.SortByID, Limit: 2, Cursor: encodeCursor(2)},
+			wantIDs: []string{"10"},
+		},
+		{
+			name:    "list_with_invalid_cursor",
+			query:   models.Query{Cursor: "invalid!"},
+			wantIDs: []string{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.List(context.Background(), tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("List() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			gotIDs := make([]string, 0)
+			for _, article := range got.Articles {
+				gotIDs = append(gotIDs, article.Id)
+			}
+			assert.Equal(t, tt.wantIDs, gotIDs)
+			assert.Equal(t, tt.wantCursor, got.NextCursor)
+		})
+	}
+}
+
+func TestCache_Put(t *testing.T) {
+	l, err := log.NewLogger(log.ERROR)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	c := NewCache(l)
+
+	article := models.Article{
+		Id:         "cms-1",
+		Title:      "test",
+		Date:       "2023-03-30",
+		Body:       "test body",
+		Tags:       []string{"fun"},
+		Source:     "cms",
+		ExternalID: "a1",
+	}
+
+	created, err := c.Put(context.Background(), &article, models.Precondition{})
+	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, int64(1), article.Version)
+
+	// replacing re-indexes the article under the new tags
+	replaced := article
+	replaced.Tags = []string{"health"}
+	created, err = c.Put(context.Background(), &replaced, models.Precondition{MatchVersions: []int64{1}})
+	assert.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, int64(2), replaced.Version)
+	_, err = c.Filter(context.Background(), "fun", 20230330)
+	assert.IsType(t, DataNotFoundError{}, err)
+	tagged, err := c.Filter(context.Background(), "health", 20230330)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"cms-1"}, tagged.Articles)
+
+	// create only does not replace the existing article
+	_, err = c.Put(context.Background(), &replaced, models.Precondition{CreateOnly: true})
+	assert.IsType(t, AlreadyExistsError{}, err)
+
+	// stale version is not replaced
+	_, err = c.Put(context.Background(), &replaced, models.Precondition{MatchVersions: []int64{1}})
+	assert.IsType(t, VersionMismatchError{}, err)
+
+	// only existing articles can be replaced with a version
+	absent := article
+	absent.Id = "cms-3"
+	_, err = c.Put(context.Background(), &absent, models.Precondition{MustExist: true})
+	assert.IsType(t, VersionMismatchError{}, err)
+
+	// provenance is unique across the articles
+	duplicate := article
+	duplicate.Id = "cms-2"
+	_, err = c.Put(context.Background(), &duplicate, models.Precondition{})
+	assert.IsType(t, ConflictError{}, err)
+	err = c.Set(context.Background(), &duplicate)
+	assert.IsType(t, ConflictError{}, err)
+
+	// the id sent on create is not taken as the owner of the provenance
+	owned := article
+	err = c.Set(context.Background(), &owned)
+	assert.IsType(t, ConflictError{}, err)
+	stored := c.(*cache)
+	assert.Len(t, stored.articles, 1)
+	assert.Equal(t, "cms-1", stored.sourceIndex[sourceKey(&article)])
+}
+
+func TestCache_FilterLastModified(t *testing.T) {
+	l, err := log.NewLogger(log.ERROR)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	c := NewCache(l)
+
+	staying := models.Article{Id: "a", Title: "test", Date: "2023-03-30", Body: "test body", Tags: []string{"fun"}}
+	moving := models.Article{Id: "b", Title: "test", Date: "2023-03-30", Body: "test body", Tags: []string{"fun"}}
+	_, err = c.Put(context.Background(), &staying, models.Precondition{})
+	assert.NoError(t, err)
+	_, err = c.Put(context.Background(), &moving, models.Precondition{})
+	assert.NoError(t, err)
+	before, err := c.Filter(context.Background(), "fun", 20230330)
+	assert.NoError(t, err)
+	assert.Equal(t, moving.StoredAt, before.LastModified)
+
+	// moving the latest article out of the index modifies the index of the remaining article
+	time.Sleep(time.Millisecond)
+	moving.Tags = []string{"health"}
+	_, err = c.Put(context.Background(), &moving, models.Precondition{})
+	assert.NoError(t, err)
+	after, err := c.Filter(context.Background(), "fun", 20230330)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a"}, after.Articles)
+	assert.True(t, after.LastModified.After(before.LastModified))
+}
@@ -1,9 +1,47 @@
 package cache
 
+import (
+	domErrors "article-dispatcher/internal/domain/errors"
+
+	"errors"
+)
+
 type DataNotFoundError struct {
 	error
 }
 
+func (DataNotFoundError) Is(target error) bool {
+	return errors.Is(domErrors.ErrNotFound, target)
+}
+
 type InvalidDataError struct {
 	error
 }
+
+func (InvalidDataError) Is(target error) bool {
+	return errors.Is(domErrors.ErrInvalid, target)
+}
+
+type ConflictError struct {
+	error
+}
+
+func (ConflictError) Is(target error) bool {
+	return errors.Is(domErrors.ErrConflict, target)
+}
+
+type AlreadyExistsError struct {
+	error
+}
+
+func (AlreadyExistsError) Is(target error) bool {
+	return errors.Is(domErrors.ErrAlreadyExists, target)
+}
+
+type VersionMismatchError struct {
+	error
+}
+
+func (VersionMismatchError) Is(target error) bool {
+	return errors.Is(domErrors.ErrVersionMismatch, target)
+}
@@ -0,0 +1,48 @@
+package cache
+
+import (
+	domErrors "article-dispatcher/internal/domain/errors"
+
+	"github.com/stretchr/testify/assert"
+
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestErrors_Is(t *testing.T) {
+	cause := errors.New("cause")
+	tests := []struct {
+		name     string
+		err      error
+		wantKind *domErrors.Kind
+		matches  []error
+		excludes []error
+	}{
+		{name: "not_found", err: DataNotFoundError{cause}, wantKind: domErrors.ErrNotFound,
+			matches: []error{domErrors.ErrNotFound}, excludes: []error{domErrors.ErrConflict, domErrors.ErrInvalid}},
+		{name: "invalid", err: InvalidDataError{cause}, wantKind: domErrors.ErrInvalid,
+			matches: []error{domErrors.ErrInvalid}, excludes: []error{domErrors.ErrNotFound}},
+		{name: "conflict", err: ConflictError{cause}, wantKind: domErrors.ErrConflict,
+			matches:  []error{domErrors.ErrConflict},
+			excludes: []error{domErrors.ErrAlreadyExists, domErrors.ErrVersionMismatch}},
+		{name: "already_exists", err: AlreadyExistsError{cause}, wantKind: domErrors.ErrAlreadyExists,
+			matches:  []error{domErrors.ErrAlreadyExists, domErrors.ErrConflict},
+			excludes: []error{domErrors.ErrVersionMismatch}},
+		{name: "version_mismatch", err: VersionMismatchError{cause}, wantKind: domErrors.ErrVersionMismatch,
+			matches:  []error{domErrors.ErrVersionMismatch, domErrors.ErrConflict},
+			excludes: []error{domErrors.ErrAlreadyExists}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wrapped := fmt.Errorf("storing article, %w", tt.err)
+			for _, target := range tt.matches {
+				assert.True(t, errors.Is(wrapped, target), target.Error())
+			}
+			for _, target := range tt.excludes {
+				assert.False(t, errors.Is(wrapped, target), target.Error())
+			}
+			assert.Equal(t, tt.wantKind, domErrors.KindOf(wrapped))
+		})
+	}
+}
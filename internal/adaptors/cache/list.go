@@ -0,0 +1,128 @@
+package cache
+
+import (
+	"article-dispatcher/internal/domain/models"
+
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// List get a sorted page of the articles matching with the query
+func (c cache) List(_ context.Context, query models.Query) (models.ArticlePage, error) {
+	page := models.ArticlePage{
+		Articles: make(models.Articles, 0),
+	}
+	offset, err := decodeCursor(query.Cursor)
+	if err != nil {
+		return page, InvalidDataError{err}
+	}
+
+	c.lock.RLock()
+	matched := make(models.Articles, 0)
+	for _, article := range c.articles {
+		if matchQuery(article, query) {
+			matched = append(matched, article)
+		}
+	}
+	c.lock.RUnlock()
+
+	sortArticles(matched, query.SortBy, query.Order)
+
+	if offset > len(matched) {
+		offset = len(matched)
+	}
+	end := len(matched)
+	if query.Limit > 0 && offset+query.Limit < end {
+		end = offset + query.Limit
+		page.NextCursor = encodeCursor(end)
+	}
+	page.Articles = append(page.Articles, matched[offset:end]...)
+
+	return page, nil
+}
+
+// matchQuery - check the article against all the non-empty query options
+func matchQuery(article models.Article, query models.Query) bool {
+	if query.DateFrom != "" && article.Date < query.DateFrom {
+		return false
+	}
+	if query.DateTo != "" && article.Date > query.DateTo {
+		return false
+	}
+	if query.TitleContains != "" &&
+		!strings.Contains(strings.ToLower(article.Title), strings.ToLower(query.TitleContains)) {
+		return false
+	}
+
+	for _, tag := range query.Tags {
+		found := false
+		for _, t := range article.Tags {
+			if t == tag {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// sortArticles - sort the articles by the given field and order, ties are broken by the article id
+// to keep the pages stable
+func sortArticles(articles models.Articles, sortBy models.SortField, order models.SortOrder) {
+	sort.SliceStable(articles, func(i, j int) bool {
+		a, b := articles[i], articles[j]
+		if order == models.SortDescending {
+			a, b = b, a
+		}
+
+		switch sortBy {
+		case models.SortByDate:
+			if a.Date != b.Date {
+				return a.Date < b.Date
+			}
+		case models.SortByTitle:
+			if a.Title != b.Title {
+				return a.Title < b.Title
+			}
+		}
+		return lessID(a.Id, b.Id)
+	})
+}
+
+// lessID - compare article ids numerically when both are numbers, otherwise lexically
+func lessID(a, b string) bool {
+	numA, errA := strconv.Atoi(a)
+	numB, errB := strconv.Atoi(b)
+	if errA == nil && errB == nil {
+		return numA < numB
+	}
+	return a < b
+}
+
+// encodeCursor - encode the offset of the next page into an opaque cursor
+func encodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset)))
+}
+
+// decodeCursor - decode the offset of the page from the cursor, empty cursor points to the first page
+func decodeCursor(cursor string) (int, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, fmt.Errorf("error, invalid cursor [%s]", cursor)
+	}
+	offset, err := strconv.Atoi(string(raw))
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("error, invalid cursor [%s]", cursor)
+	}
+	return offset, nil
+}
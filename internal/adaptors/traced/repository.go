@@ -0,0 +1,115 @@
+package traced
+
+import (
+	"article-dispatcher/internal/domain/adaptors/repository"
+	"article-dispatcher/internal/domain/adaptors/tracer"
+	"article-dispatcher/internal/domain/models"
+	"context"
+)
+
+// traced repository decorator timing every call of the repository in a span
+type traced struct {
+	tracer tracer.Tracer
+	repo   repository.Repository
+}
+
+func NewRepository(t tracer.Tracer, repo repository.Repository) repository.Repository {
+	return &traced{
+		tracer: t,
+		repo:   repo,
+	}
+}
+
+func (t traced) Set(ctx context.Context, article *models.Article) error {
+	ctx, span := t.tracer.Start(ctx, "Repository.Set")
+	defer span.End()
+
+	err := t.repo.Set(ctx, article)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	span.SetAttribute(tracer.AttributeArticleID, article.Id)
+	return nil
+}
+
+func (t traced) Put(ctx context.Context, article *models.Article, precondition models.Precondition) (bool, error) {
+	ctx, span := t.tracer.Start(ctx, "Repository.Put")
+	defer span.End()
+	span.SetAttribute(tracer.AttributeArticleID, article.Id)
+
+	created, err := t.repo.Put(ctx, article, precondition)
+	if err != nil {
+		span.RecordError(err)
+		return created, err
+	}
+	span.SetAttribute(tracer.AttributeCreated, created)
+	return created, nil
+}
+
+func (t traced) Get(ctx context.Context, id string) (models.Article, error) {
+	ctx, span := t.tracer.Start(ctx, "Repository.Get")
+	defer span.End()
+	span.SetAttribute(tracer.AttributeArticleID, id)
+
+	article, err := t.repo.Get(ctx, id)
+	span.RecordError(err)
+	return article, err
+}
+
+func (t traced) GetMany(ctx context.Context, ids []string) (models.BatchArticles, error) {
+	ctx, span := t.tracer.Start(ctx, "Repository.GetMany")
+	defer span.End()
+	span.SetAttribute(tracer.AttributeIDCount, len(ids))
+
+	articles, err := t.repo.GetMany(ctx, ids)
+	if err != nil {
+		span.RecordError(err)
+		return articles, err
+	}
+	span.SetAttribute(tracer.AttributeResultCount, len(articles.Articles))
+	return articles, nil
+}
+
+func (t traced) Filter(ctx context.Context, tag string, date int) (models.TaggedArticles, error) {
+	ctx, span := t.tracer.Start(ctx, "Repository.Filter")
+	defer span.End()
+	span.SetAttribute(tracer.AttributeTag, tag)
+	span.SetAttribute(tracer.AttributeDate, date)
+
+	taggedArticles, err := t.repo.Filter(ctx, tag, date)
+	if err != nil {
+		span.RecordError(err)
+		return taggedArticles, err
+	}
+	span.SetAttribute(tracer.AttributeResultCount, len(taggedArticles.Articles))
+	return taggedArticles, nil
+}
+
+func (t traced) FilterExpanded(ctx context.Context, tag string, date int) (models.ExpandedTaggedArticles, error) {
+	ctx, span := t.tracer.Start(ctx, "Repository.FilterExpanded")
+	defer span.End()
+	span.SetAttribute(tracer.AttributeTag, tag)
+	span.SetAttribute(tracer.AttributeDate, date)
+
+	taggedArticles, err := t.repo.FilterExpanded(ctx, tag, date)
+	if err != nil {
+		span.RecordError(err)
+		return taggedArticles, err
+	}
+	span.SetAttribute(tracer.AttributeResultCount, len(taggedArticles.Articles))
+	return taggedArticles, nil
+}
+
+func (t traced) List(ctx context.Context, query models.Query) (models.ArticlePage, error) {
+	ctx, span := t.tracer.Start(ctx, "Repository.List")
+	defer span.End()
+
+	page, err := t.repo.List(ctx, query)
+	if err != nil {
+		span.RecordError(err)
+		return page, err
+	}
+	span.SetAttribute(tracer.AttributeResultCount, len(page.Articles))
+	return page, nil
+}
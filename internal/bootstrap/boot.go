@@ -2,38 +2,73 @@ package bootstrap
 
 import (
 	"article-dispatcher/internal/adaptors/cache"
+	"article-dispatcher/internal/adaptors/traced"
 	"article-dispatcher/internal/domain/adaptors/logger"
+	"article-dispatcher/internal/domain/adaptors/tracer"
 	"article-dispatcher/internal/http"
+	"article-dispatcher/internal/http/handlers"
 	"article-dispatcher/internal/pkg/configs"
 	"article-dispatcher/internal/pkg/log"
 	"article-dispatcher/internal/pkg/metrics"
+	"article-dispatcher/internal/pkg/tracing"
 	"article-dispatcher/internal/services"
+	"context"
 	"fmt"
 	sysLog "log"
 	"os"
 	"os/signal"
+	"syscall"
 )
 
 // Boot - initialize configurations, logger and create necessary dependency plugins
 // init the router and serve the routes
 func Boot() {
 	initConfigs()
-	l := initLogger()
+	l, levels, sink := initLogger()
 	m := initMetrics(l)
+	t, stopTracer := initTracer(l)
+	accessSink := initAccessLog()
 
 	// plugin a cache to the repository
-	repo := cache.NewCache(l)
-	articleService := services.NewArticleService(l, repo)
+	repo := traced.NewRepository(t, cache.NewCache(l))
+	articleService := services.NewArticleService(l, repo, t)
 
 	r := &http.Router{
-		Conf: &http.Config,
+		Conf:                &http.Config,
+		RateLimitRejections: metrics.RateLimitRejections,
+		Tracer:              t,
+		LogLevels:           levels,
+		AccessLogSink:       accessSink,
+	}
+	if err := r.Init(l, articleService, metrics.RequestLatency); err != nil {
+		sysLog.Fatalln("error initializing the router due to: ", err)
 	}
-	r.Init(l, articleService, metrics.RequestLatency)
 
 	// interrupt channel to stop the servers
 	signals := make(chan os.Signal, 1)
 	signal.Notify(signals, os.Interrupt)
 
+	// hangup channel to reload the api keys, the jwt verification keys and the log levels
+	reloads := make(chan os.Signal, 1)
+	signal.Notify(reloads, syscall.SIGHUP)
+	go func() {
+		for range reloads {
+			if err := r.ReloadKeys(); err != nil {
+				l.Error("failed to reload the authentication keys", logger.F("error", err))
+			} else {
+				l.Info("authentication keys reloaded")
+			}
+			if log.Config.ConfigFile == "" {
+				continue
+			}
+			if err := applyLogConfig(levels); err != nil {
+				l.Error("failed to reload the log levels", logger.F("error", err))
+				continue
+			}
+			l.Info("log levels reloaded", logger.F("level", levels.Level()))
+		}
+	}()
+
 	// channel to ensure graceful shutdown of the servers
 	exitAll := make(chan bool, 1)
 
@@ -45,6 +80,18 @@ func Boot() {
 		if err := m.Stop(); err != nil {
 			sysLog.Fatalf(fmt.Sprintf("failed to gracefully shutdown the metrics server due to: %s", err))
 		}
+		if err := stopTracer(); err != nil {
+			l.Error("failed to export the remaining spans", logger.F("error", err))
+		}
+		if accessSink != nil {
+			if err := accessSink.Close(); err != nil {
+				l.Error("failed to flush the access log", logger.F("error", err))
+			}
+		}
+		// flush the buffered log entries last, so that the shutdown is logged
+		if err := sink.Close(); err != nil {
+			sysLog.Printf("failed to flush the log sinks due to: %s", err)
+		}
 		exitAll <- true
 	}()
 
@@ -63,6 +110,7 @@ func initConfigs() {
 		new(http.RouterConfig),
 		new(log.LoggerConfig),
 		new(metrics.MetricConfig),
+		new(tracing.TracingConfig),
 	)
 
 	if err != nil {
@@ -70,13 +118,71 @@ func initConfigs() {
 	}
 }
 
-// initLogger - init logger with log level defined in the environment
-func initLogger() logger.Logger {
-	l, err := log.NewLogger(log.Config.Level)
+// initLogger - init logger with log level defined in the environment, overridden by the log config file when set,
+// returning the levels changeable at runtime and the sink to flush on shutdown
+func initLogger() (logger.Logger, *log.Levels, log.Sink) {
+	levels, err := log.NewLevels(log.Config.Level, log.Config.OverrideTTL)
+	if err != nil {
+		sysLog.Fatalln("error loading log levels due to: ", err)
+	}
+	if log.Config.ConfigFile != "" {
+		if err = applyLogConfig(levels); err != nil {
+			sysLog.Fatalln("error loading log levels due to: ", err)
+		}
+	}
+	sink, err := log.Config.NewSink()
+	if err != nil {
+		sysLog.Fatalln("error loading log sinks due to: ", err)
+	}
+	l, err := log.NewLogger(log.Config.Level, log.WithLevels(levels), log.WithFormat(log.Config.Format),
+		log.WithService(log.Config.ServiceName), log.WithSink(sink))
 	if err != nil {
 		sysLog.Fatalln("error loading new logger due to: ", err)
 	}
-	return l
+	return l, levels, sink
+}
+
+// accessLogName service name of the reports of the access log entries dropped while the buffer is full
+const accessLogName = "access-log"
+
+// initAccessLog - init the sink of the access log when enabled, buffered like the log sinks and rotated with the
+// settings of the log file
+func initAccessLog() log.Sink {
+	if !http.Config.AccessLog.Enabled {
+		return nil
+	}
+	var sink log.Sink
+	switch http.Config.AccessLog.Output {
+	case http.AccessLogStdout:
+		sink = log.NewWriterSink(os.Stdout)
+	case http.AccessLogStderr:
+		sink = log.NewWriterSink(os.Stderr)
+	case http.AccessLogFile:
+		fs, err := log.NewFileSink(http.Config.AccessLog.File, log.Config.File.MaxSize, log.Config.File.MaxAge,
+			log.Config.File.MaxBackups, log.Config.File.Compress)
+		if err != nil {
+			sysLog.Fatalln("error opening the access log file due to: ", err)
+		}
+		sink = fs
+	}
+	if log.Config.BufferSize > 0 {
+		// the dropped entries are reported along the access entries, as json for the json entries and as text otherwise
+		format := log.FormatText
+		if http.Config.AccessLog.Format == handlers.AccessLogJSON {
+			format = log.FormatJSON
+		}
+		sink = log.NewAsyncSink(sink, log.Config.BufferSize, format, accessLogName)
+	}
+	return sink
+}
+
+// applyLogConfig - apply the levels of the log config file
+func applyLogConfig(levels *log.Levels) error {
+	c, err := log.LoadLevelConfig(log.Config.ConfigFile)
+	if err != nil {
+		return err
+	}
+	return levels.Apply(c)
 }
 
 // initMetrics - init metrics and start non-blocking metrics router
@@ -98,3 +204,21 @@ func initMetrics(l logger.Logger) *metrics.RouterMetrics {
 	}()
 	return m
 }
+
+// initTracer - init the tracer exporting the spans when tracing is enabled, returning the function flushing the
+// remaining spans on shutdown
+func initTracer(l logger.Logger) (tracer.Tracer, func() error) {
+	if !tracing.Conf.Enabled {
+		return tracing.NoopTracer{}, func() error { return nil }
+	}
+	exporter, err := tracing.Conf.NewExporter(os.Stdout)
+	if err != nil {
+		sysLog.Fatalln("error creating the span exporter due to: ", err)
+	}
+	t := tracing.NewTracer(l, exporter, tracing.Conf.Options())
+	return t, func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), tracing.Conf.OTLP.Timeout)
+		defer cancel()
+		return t.Shutdown(ctx)
+	}
+}
@@ -0,0 +1,20 @@
+package logger
+
+import (
+	"context"
+)
+
+type contextKey struct{}
+
+// NewContext context carrying the logger, entries logged with it during a request are annotated with the request
+func NewContext(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext logger of the context, otherwise the fallback logger
+func FromContext(ctx context.Context, fallback Logger) Logger {
+	if l, ok := ctx.Value(contextKey{}).(Logger); ok {
+		return l
+	}
+	return fallback
+}
@@ -1,11 +1,24 @@
 package logger
 
-// Logger interface for logging within the application
+// Logger interface for logging within the application, the fields annotate the entry with key/value pairs
+// With - child logger annotating all of its entries with the fields
 type Logger interface {
-	Fatal(message string)
-	Error(message string)
-	Warn(message string)
-	Debug(message string)
-	Info(message string)
-	Trace(message string)
+	Fatal(message string, fields ...Field)
+	Error(message string, fields ...Field)
+	Warn(message string, fields ...Field)
+	Debug(message string, fields ...Field)
+	Info(message string, fields ...Field)
+	Trace(message string, fields ...Field)
+	With(fields ...Field) Logger
+}
+
+// Field key/value pair of a log entry
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// F field of the key and the value
+func F(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
 }
@@ -7,10 +7,18 @@ import (
 
 // Repository high-level methods to the repository function
 // Set - insert the value into the repository
+// Put - insert the value with its own id into the repository or replace the existing one when the precondition holds
 // Get - retrieve data from repository
+// GetMany - retrieve several articles from repository in one call, reporting the missing ids
 // Filter - fetch conditioned articles data from repository
+// FilterExpanded - fetch conditioned articles data from repository along with the full articles
+// List - fetch a sorted page of articles matching the query from repository
 type Repository interface {
 	Set(ctx context.Context, article *models.Article) error
+	Put(ctx context.Context, article *models.Article, precondition models.Precondition) (created bool, err error)
 	Get(ctx context.Context, id string) (models.Article, error)
+	GetMany(ctx context.Context, ids []string) (models.BatchArticles, error)
 	Filter(ctx context.Context, tag string, date int) (models.TaggedArticles, error)
+	FilterExpanded(ctx context.Context, tag string, date int) (models.ExpandedTaggedArticles, error)
+	List(ctx context.Context, query models.Query) (models.ArticlePage, error)
 }
@@ -0,0 +1,33 @@
+package tracer
+
+import (
+	"context"
+)
+
+// attribute keys shared by the spans of the application layers
+const (
+	AttributeArticleID   = "article.id"
+	AttributeIDCount     = "article.id_count"
+	AttributeTag         = "article.tag"
+	AttributeDate        = "article.date"
+	AttributeResultCount = "result.count"
+	AttributeCreated     = "article.created"
+	AttributeErrorKind   = "error.kind"
+)
+
+// Tracer start spans timing the units of work of a request
+// Start - start a span named name, child of the span of the context when there is one,
+// the returned context carries the started span
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span timed unit of work
+// SetAttribute - annotate the span with the value of the key
+// RecordError - mark the span as failed with the error and its domain error kind
+// End - finish the span, it is exported once ended
+type Span interface {
+	SetAttribute(key string, value interface{})
+	RecordError(err error)
+	End()
+}
@@ -0,0 +1,55 @@
+package errors
+
+import (
+	"errors"
+)
+
+// Kind kind of the domain errors, adaptors report the kind of their errors through `errors.Is` so that the callers
+// can handle them without depending on the adaptor. A kind can refine a parent kind, matching both of them
+type Kind struct {
+	name   string
+	parent *Kind
+}
+
+func (k *Kind) Error() string {
+	return k.name
+}
+
+// Unwrap parent kind of the kind
+func (k *Kind) Unwrap() error {
+	if k.parent == nil {
+		return nil
+	}
+	return k.parent
+}
+
+var (
+	// ErrNotFound requested entity does not exist
+	ErrNotFound = &Kind{name: "not found"}
+	// ErrInvalid entity or request is invalid
+	ErrInvalid = &Kind{name: "invalid"}
+	// ErrConflict write conflicts with the stored entities
+	ErrConflict = &Kind{name: "conflict"}
+	// ErrUnavailable dependency is temporarily unavailable, the request can be retried
+	ErrUnavailable = &Kind{name: "unavailable"}
+	// ErrInternal unexpected failure of the application
+	ErrInternal = &Kind{name: "internal"}
+
+	// ErrAlreadyExists create only write of an existing entity
+	ErrAlreadyExists = &Kind{name: "already exists", parent: ErrConflict}
+	// ErrVersionMismatch conditional write of an entity which is not at the expected version
+	ErrVersionMismatch = &Kind{name: "version mismatch", parent: ErrConflict}
+)
+
+// kinds domain error kinds, refined kinds are listed before their parents
+var kinds = []*Kind{ErrAlreadyExists, ErrVersionMismatch, ErrNotFound, ErrInvalid, ErrConflict, ErrUnavailable, ErrInternal}
+
+// KindOf most refined domain error kind of the error, nil when the error has no kind
+func KindOf(err error) *Kind {
+	for _, kind := range kinds {
+		if errors.Is(err, kind) {
+			return kind
+		}
+	}
+	return nil
+}
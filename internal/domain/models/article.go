@@ -1,19 +1,47 @@
 package models
 
+import "time"
+
+// Article the article with its tags and the optional provenance of the source system it was imported from
 // nolint:stylecheck
 type Article struct {
 	Id    string   `json:"id"`
-	Title string   `json:"title"`
-	Date  string   `json:"date" validate:"datetime=2006-01-02"`
-	Body  string   `json:"body"`
-	Tags  []string `json:"tags"`
+	Title string   `json:"title" validate:"required,max=255"`
+	Date  string   `json:"date" validate:"required,datetime=2006-01-02,maxfuture=8760h"`
+	Body  string   `json:"body" validate:"required,max=100000"`
+	Tags  []string `json:"tags" validate:"required,min=1,max=20,unique,dive,required,max=50,tagname"`
+	// Source and ExternalID are unique together
+	Source     string `json:"source,omitempty" validate:"required_with=ExternalID,max=64"`
+	ExternalID string `json:"external_id,omitempty" validate:"required_with=Source,max=128"`
+	// Version incremented by the repository on every write
+	Version int64 `json:"version"`
+	// StoredAt time the article was last written in the repository
+	StoredAt time.Time `json:"-"`
 }
 
 type Articles []Article
 
+// TaggedArticles the latest articles of a tag and date, last modified is the last time an article was added to
+// or removed from the tag and date
 type TaggedArticles struct {
-	Tag         string   `json:"tag"`
-	Count       int      `json:"count"`
-	Articles    []string `json:"articles"`
-	RelatedTags []string `json:"related_tags"`
+	Tag          string    `json:"tag"`
+	Count        int       `json:"count"`
+	Articles     []string  `json:"articles"`
+	RelatedTags  []string  `json:"related_tags"`
+	LastModified time.Time `json:"-"`
+}
+
+// ExpandedTaggedArticles tagged articles embedding the full articles instead of the bare article ids
+type ExpandedTaggedArticles struct {
+	Tag          string    `json:"tag"`
+	Count        int       `json:"count"`
+	Articles     Articles  `json:"articles"`
+	RelatedTags  []string  `json:"related_tags"`
+	LastModified time.Time `json:"-"`
+}
+
+// BatchArticles articles found for a batch of ids along with the ids which were not found
+type BatchArticles struct {
+	Articles Articles `json:"articles"`
+	Missing  []string `json:"missing"`
 }
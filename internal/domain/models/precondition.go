@@ -0,0 +1,11 @@
+package models
+
+// Precondition write preconditions checked by the repository atomically with the write
+// CreateOnly - the article must not exist
+// MustExist - the article must exist
+// MatchVersions - the stored article must be at one of the versions, empty skips the check
+type Precondition struct {
+	CreateOnly    bool
+	MustExist     bool
+	MatchVersions []int64
+}
@@ -0,0 +1,38 @@
+package models
+
+type SortField string
+
+type SortOrder string
+
+// sort fields and orders supported when listing articles
+const (
+	SortByDate  SortField = "date"
+	SortByID    SortField = "id"
+	SortByTitle SortField = "title"
+
+	SortAscending  SortOrder = "asc"
+	SortDescending SortOrder = "desc"
+)
+
+// Query article listing options, empty options are not applied
+// Tags - articles must contain all the tags
+// DateFrom, DateTo - inclusive article date range in `yyyy-mm-dd` format
+// TitleContains - case-insensitive part of the article title
+// Limit - maximum number of articles in a page, zero returns all the articles
+// Cursor - opaque cursor returned with the previous page
+type Query struct {
+	Tags          []string
+	DateFrom      string
+	DateTo        string
+	TitleContains string
+	SortBy        SortField
+	Order         SortOrder
+	Limit         int
+	Cursor        string
+}
+
+// ArticlePage a page of listed articles with the cursor of the next page, cursor is empty on the last page
+type ArticlePage struct {
+	Articles   Articles `json:"articles"`
+	NextCursor string   `json:"next_cursor,omitempty"`
+}
@@ -8,6 +8,10 @@ import (
 // ArticleService create the article in the system
 type ArticleService interface {
 	Create(ctx context.Context, article *models.Article) error
+	Put(ctx context.Context, article *models.Article, precondition models.Precondition) (created bool, err error)
 	Get(ctx context.Context, id string) (models.Article, error)
+	GetMany(ctx context.Context, ids []string) (models.BatchArticles, error)
 	Filter(ctx context.Context, tag string, date int) (models.TaggedArticles, error)
+	FilterExpanded(ctx context.Context, tag string, date int) (models.ExpandedTaggedArticles, error)
+	List(ctx context.Context, query models.Query) (models.ArticlePage, error)
 }
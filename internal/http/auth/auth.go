@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"errors"
+)
+
+// Scope permission granted to a caller
+type Scope string
+
+const (
+	ScopeRead  Scope = "articles:read"
+	ScopeWrite Scope = "articles:write"
+	// ScopeAdmin grants every other scope
+	ScopeAdmin Scope = "admin"
+	// ScopePublic routes which do not require authentication
+	ScopePublic Scope = "public"
+)
+
+var (
+	// ErrMissingCredentials request does not carry any credentials
+	ErrMissingCredentials = errors.New("missing credentials")
+	// ErrInvalidCredentials credentials do not belong to a known caller
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+// Identity authenticated caller with its granted scopes, claims are only set for bearer tokens
+type Identity struct {
+	ID     string
+	Scopes []Scope
+	Claims *Claims
+}
+
+// HasScope check if the identity was granted the scope
+func (i Identity) HasScope(scope Scope) bool {
+	for _, s := range i.Scopes {
+		if s == scope || s == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// validScope check if the scope is a known scope
+func validScope(scope Scope) bool {
+	switch scope {
+	case ScopeRead, ScopeWrite, ScopeAdmin:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Authenticator authenticate the credentials of the request
+// Authenticate - identity of the request, returns ErrMissingCredentials when the request has none of its credentials
+// Challenge - `WWW-Authenticate` challenge sent with the unauthenticated responses
+type Authenticator interface {
+	Authenticate(request *http.Request) (Identity, error)
+	Challenge() string
+}
+
+// APIKeyAuthenticator authenticate the api key sent in the header
+type APIKeyAuthenticator struct {
+	Store  KeyStore
+	Header string
+}
+
+func (a APIKeyAuthenticator) Authenticate(request *http.Request) (Identity, error) {
+	return a.Store.Authenticate(request.Header.Get(a.Header))
+}
+
+func (a APIKeyAuthenticator) Challenge() string {
+	return fmt.Sprintf(`APIKey header="%s"`, a.Header)
+}
+
+// BearerAuthenticator authenticate the jwt sent as an `Authorization: Bearer` token
+type BearerAuthenticator struct {
+	Verifier *JWTVerifier
+}
+
+func (a BearerAuthenticator) Authenticate(request *http.Request) (Identity, error) {
+	parts := strings.SplitN(request.Header.Get("Authorization"), " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
+		return Identity{}, ErrMissingCredentials
+	}
+	claims, err := a.Verifier.Verify(strings.TrimSpace(parts[1]))
+	if err != nil {
+		return Identity{}, err
+	}
+
+	var scopes []Scope
+	for _, s := range strings.Fields(claims.Scope) {
+		if validScope(Scope(s)) {
+			scopes = append(scopes, Scope(s))
+		}
+	}
+	return Identity{ID: claims.Subject, Scopes: scopes, Claims: &claims}, nil
+}
+
+func (a BearerAuthenticator) Challenge() string {
+	return `Bearer error="invalid_token"`
+}
@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+// verificationKey key verifying the signatures of a single algorithm, an empty id matches every key id
+type verificationKey struct {
+	id        string
+	algorithm string
+	key       interface{}
+}
+
+// jwks json web key set holding RSA and symmetric keys
+type jwks struct {
+	Keys []struct {
+		KeyType   string `json:"kty"`
+		KeyID     string `json:"kid"`
+		Algorithm string `json:"alg"`
+		Use       string `json:"use"`
+		N         string `json:"n"`
+		E         string `json:"e"`
+		K         string `json:"k"`
+	} `json:"keys"`
+}
+
+// loadKeys load the secret, the PEM public key and the JWKS keys of the config
+func loadKeys(conf JWTConfig) ([]verificationKey, error) {
+	var keys []verificationKey
+	if conf.Secret != "" {
+		keys = append(keys, verificationKey{algorithm: AlgorithmHS256, key: []byte(conf.Secret)})
+	}
+	if conf.PublicKeyFile != "" {
+		key, err := loadPublicKey(conf.PublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, verificationKey{algorithm: AlgorithmRS256, key: key})
+	}
+	if conf.JWKSFile != "" {
+		set, err := loadJWKS(conf.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, set...)
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no jwt verification keys configured")
+	}
+	return keys, nil
+}
+
+// loadPublicKey load the PEM encoded RSA public key
+func loadPublicKey(path string) (*rsa.PublicKey, error) {
+	byt, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading public key file [%s] due to, %w", path, err)
+	}
+	block, _ := pem.Decode(byt)
+	if block == nil {
+		return nil, fmt.Errorf("public key file [%s] is not PEM encoded", path)
+	}
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing public key file [%s] due to, %w", path, err)
+	}
+	key, ok := parsed.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key file [%s] is not an RSA key", path)
+	}
+	return key, nil
+}
+
+// loadJWKS load the signature keys of the JWKS file, the algorithm is derived from the key type when not given
+func loadJWKS(path string) ([]verificationKey, error) {
+	byt, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading jwks file [%s] due to, %w", path, err)
+	}
+	var set jwks
+	if err = json.Unmarshal(byt, &set); err != nil {
+		return nil, fmt.Errorf("error decoding jwks file [%s] due to, %w", path, err)
+	}
+
+	keys := make([]verificationKey, 0, len(set.Keys))
+	for i, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		switch k.KeyType {
+		case "RSA":
+			if k.Algorithm != "" && k.Algorithm != AlgorithmRS256 {
+				return nil, fmt.Errorf("jwks key at index [%d] has an unsupported algorithm [%s]", i, k.Algorithm)
+			}
+			n, err := base64.RawURLEncoding.DecodeString(k.N)
+			if err != nil {
+				return nil, fmt.Errorf("jwks key at index [%d] has an invalid modulus", i)
+			}
+			e, err := base64.RawURLEncoding.DecodeString(k.E)
+			if err != nil || len(e) == 0 || len(e) > 4 {
+				return nil, fmt.Errorf("jwks key at index [%d] has an invalid exponent", i)
+			}
+			key := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+			keys = append(keys, verificationKey{id: k.KeyID, algorithm: AlgorithmRS256, key: key})
+		case "oct":
+			if k.Algorithm != "" && k.Algorithm != AlgorithmHS256 {
+				return nil, fmt.Errorf("jwks key at index [%d] has an unsupported algorithm [%s]", i, k.Algorithm)
+			}
+			secret, err := base64.RawURLEncoding.DecodeString(k.K)
+			if err != nil || len(secret) == 0 {
+				return nil, fmt.Errorf("jwks key at index [%d] has an invalid secret", i)
+			}
+			keys = append(keys, verificationKey{id: k.KeyID, algorithm: AlgorithmHS256, key: secret})
+		default:
+			return nil, fmt.Errorf("jwks key at index [%d] has an unsupported key type [%s]", i, k.KeyType)
+		}
+	}
+	return keys, nil
+}
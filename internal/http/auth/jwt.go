@@ -0,0 +1,219 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+)
+
+var (
+	// ErrTokenMalformed token is not a compact serialized jwt
+	ErrTokenMalformed = errors.New("malformed token")
+	// ErrTokenSignature token is not signed by any of the verification keys
+	ErrTokenSignature = errors.New("invalid token signature")
+	// ErrTokenAlgorithm token is signed with an unsupported algorithm
+	ErrTokenAlgorithm = errors.New("unsupported token algorithm")
+	// ErrTokenExpired token is expired, or has no expiry
+	ErrTokenExpired = errors.New("token expired")
+	// ErrTokenNotYetValid token is used before its not before time
+	ErrTokenNotYetValid = errors.New("token not yet valid")
+	// ErrTokenIssuer token is not issued by the expected issuer
+	ErrTokenIssuer = errors.New("invalid token issuer")
+	// ErrTokenAudience token is not intended for the expected audience
+	ErrTokenAudience = errors.New("invalid token audience")
+)
+
+// NumericDate jwt date in seconds since the epoch
+type NumericDate struct {
+	time.Time
+}
+
+func (d *NumericDate) UnmarshalJSON(byt []byte) error {
+	var seconds float64
+	if err := json.Unmarshal(byt, &seconds); err != nil {
+		return fmt.Errorf("invalid numeric date due to, %w", err)
+	}
+	whole, fraction := math.Modf(seconds)
+	d.Time = time.Unix(int64(whole), int64(fraction*1e9)).UTC()
+	return nil
+}
+
+// Audience jwt audience, either a single string or a list of strings
+type Audience []string
+
+func (a *Audience) UnmarshalJSON(byt []byte) error {
+	var single string
+	if err := json.Unmarshal(byt, &single); err == nil {
+		*a = Audience{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(byt, &list); err != nil {
+		return fmt.Errorf("invalid audience due to, %w", err)
+	}
+	*a = list
+	return nil
+}
+
+// Claims registered claims of the jwt with the space separated scopes granted to the subject
+type Claims struct {
+	Issuer    string       `json:"iss"`
+	Subject   string       `json:"sub"`
+	Audience  Audience     `json:"aud"`
+	ExpiresAt *NumericDate `json:"exp"`
+	NotBefore *NumericDate `json:"nbf"`
+	IssuedAt  *NumericDate `json:"iat"`
+	ID        string       `json:"jti"`
+	Scope     string       `json:"scope"`
+}
+
+type header struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+}
+
+// JWTConfig verification keys and the expected claims of the tokens, the issuer and audience are only checked
+// when configured
+type JWTConfig struct {
+	Secret        string
+	PublicKeyFile string
+	JWKSFile      string
+	Issuer        string
+	Audience      string
+	Leeway        time.Duration
+}
+
+// JWTVerifier verify HS256 and RS256 signed tokens
+type JWTVerifier struct {
+	lock *sync.RWMutex
+	conf JWTConfig
+	keys []verificationKey
+	now  func() time.Time
+}
+
+// NewJWTVerifier create a verifier loading the verification keys of the config
+func NewJWTVerifier(conf JWTConfig) (*JWTVerifier, error) {
+	v := &JWTVerifier{
+		lock: &sync.RWMutex{},
+		conf: conf,
+		now:  time.Now,
+	}
+	if err := v.Reload(); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Reload reload the verification keys from the configured files, the current keys are kept when the reload fails
+func (v *JWTVerifier) Reload() error {
+	keys, err := loadKeys(v.conf)
+	if err != nil {
+		return err
+	}
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	v.keys = keys
+	return nil
+}
+
+// Verify verify the signature and the claims of the token
+func (v *JWTVerifier) Verify(token string) (Claims, error) {
+	var claims Claims
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return claims, ErrTokenMalformed
+	}
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return claims, err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return claims, ErrTokenMalformed
+	}
+	if err = v.verifySignature(h, parts[0]+"."+parts[1], signature); err != nil {
+		return claims, err
+	}
+	if err = decodeSegment(parts[1], &claims); err != nil {
+		return claims, err
+	}
+	return claims, v.validateClaims(claims)
+}
+
+// verifySignature verify the signature with the keys of the token algorithm, narrowed to the key id when given
+func (v *JWTVerifier) verifySignature(h header, signed string, signature []byte) error {
+	if h.Algorithm != AlgorithmHS256 && h.Algorithm != AlgorithmRS256 {
+		return fmt.Errorf("%w [%s]", ErrTokenAlgorithm, h.Algorithm)
+	}
+	digest := sha256.Sum256([]byte(signed))
+
+	v.lock.RLock()
+	defer v.lock.RUnlock()
+	for _, k := range v.keys {
+		if k.algorithm != h.Algorithm || (h.KeyID != "" && k.id != "" && k.id != h.KeyID) {
+			continue
+		}
+		switch key := k.key.(type) {
+		case []byte:
+			mac := hmac.New(sha256.New, key)
+			mac.Write([]byte(signed))
+			if hmac.Equal(mac.Sum(nil), signature) {
+				return nil
+			}
+		case *rsa.PublicKey:
+			if rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature) == nil {
+				return nil
+			}
+		}
+	}
+	return ErrTokenSignature
+}
+
+// validateClaims validate the time window, issuer and audience of the claims
+func (v *JWTVerifier) validateClaims(claims Claims) error {
+	now := v.now()
+	if claims.ExpiresAt == nil || !now.Before(claims.ExpiresAt.Add(v.conf.Leeway)) {
+		return ErrTokenExpired
+	}
+	if claims.NotBefore != nil && now.Add(v.conf.Leeway).Before(claims.NotBefore.Time) {
+		return ErrTokenNotYetValid
+	}
+	if v.conf.Issuer != "" && claims.Issuer != v.conf.Issuer {
+		return fmt.Errorf("%w [%s]", ErrTokenIssuer, claims.Issuer)
+	}
+	if v.conf.Audience != "" {
+		for _, aud := range claims.Audience {
+			if aud == v.conf.Audience {
+				return nil
+			}
+		}
+		return fmt.Errorf("%w %v", ErrTokenAudience, []string(claims.Audience))
+	}
+	return nil
+}
+
+// decodeSegment decode the base64 url encoded json segment of the token
+func decodeSegment(segment string, v interface{}) error {
+	byt, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return ErrTokenMalformed
+	}
+	if err = json.Unmarshal(byt, v); err != nil {
+		return fmt.Errorf("%w, %s", ErrTokenMalformed, err)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package auth
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func sign(t *testing.T, alg, kid string, claims map[string]interface{}, key interface{}) string {
+	h, err := json.Marshal(map[string]string{"alg": alg, "typ": "JWT", "kid": kid})
+	assert.NoError(t, err)
+	c, err := json.Marshal(claims)
+	assert.NoError(t, err)
+	signed := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c)
+
+	var signature []byte
+	switch k := key.(type) {
+	case []byte:
+		mac := hmac.New(sha256.New, k)
+		mac.Write([]byte(signed))
+		signature = mac.Sum(nil)
+	case *rsa.PrivateKey:
+		digest := sha256.Sum256([]byte(signed))
+		signature, err = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:])
+		assert.NoError(t, err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestJWTVerifier(t *testing.T) {
+	now := time.Date(2023, 3, 30, 0, 0, 0, 0, time.UTC)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	jwksFile := fmt.Sprintf(`{"keys":[{"kty":"RSA","kid":"rsa-1","use":"sig","n":"%s","e":"%s"}]}`,
+		base64.RawURLEncoding.EncodeToString(rsaKey.N.Bytes()),
+		base64.RawURLEncoding.EncodeToString(big.NewInt(int64(rsaKey.E)).Bytes()))
+	assert.NoError(t, os.WriteFile(path, []byte(jwksFile), 0o600))
+
+	v, err := NewJWTVerifier(JWTConfig{
+		Secret:   "secret",
+		JWKSFile: path,
+		Issuer:   "issuer",
+		Audience: "article-dispatcher",
+		Leeway:   time.Minute,
+	})
+	assert.NoError(t, err)
+	v.now = func() time.Time { return now }
+
+	claims := func(overrides map[string]interface{}) map[string]interface{} {
+		c := map[string]interface{}{
+			"iss":   "issuer",
+			"sub":   "cms",
+			"aud":   []string{"article-dispatcher", "other"},
+			"exp":   now.Add(time.Hour).Unix(),
+			"scope": "articles:read articles:write",
+		}
+		for k, value := range overrides {
+			c[k] = value
+		}
+		return c
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		err   error
+	}{
+		{"hs256", sign(t, AlgorithmHS256, "", claims(nil), []byte("secret")), nil},
+		{"rs256", sign(t, AlgorithmRS256, "rsa-1", claims(map[string]interface{}{"aud": "article-dispatcher"}), rsaKey), nil},
+		{"rs256 without key id", sign(t, AlgorithmRS256, "", claims(nil), rsaKey), nil},
+		{"unknown key id", sign(t, AlgorithmRS256, "rsa-2", claims(nil), rsaKey), ErrTokenSignature},
+		{"wrong secret", sign(t, AlgorithmHS256, "", claims(nil), []byte("other")), ErrTokenSignature},
+		{"none algorithm", sign(t, "none", "", claims(nil), nil), ErrTokenAlgorithm},
+		{"expired within leeway", sign(t, AlgorithmHS256, "", claims(map[string]interface{}{
+			"exp": now.Add(-30 * time.Second).Unix()}), []byte("secret")), nil},
+		{"expired", sign(t, AlgorithmHS256, "", claims(map[string]interface{}{
+			"exp": now.Add(-time.Hour).Unix()}), []byte("secret")), ErrTokenExpired},
+		{"without expiry", sign(t, AlgorithmHS256, "", claims(map[string]interface{}{
+			"exp": nil}), []byte("secret")), ErrTokenExpired},
+		{"not yet valid", sign(t, AlgorithmHS256, "", claims(map[string]interface{}{
+			"nbf": now.Add(time.Hour).Unix()}), []byte("secret")), ErrTokenNotYetValid},
+		{"wrong issuer", sign(t, AlgorithmHS256, "", claims(map[string]interface{}{
+			"iss": "other"}), []byte("secret")), ErrTokenIssuer},
+		{"wrong audience", sign(t, AlgorithmHS256, "", claims(map[string]interface{}{
+			"aud": "other"}), []byte("secret")), ErrTokenAudience},
+		{"malformed", "not.a-token", ErrTokenMalformed},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := v.Verify(test.token)
+			if test.err == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, "cms", got.Subject)
+				return
+			}
+			assert.ErrorIs(t, err, test.err)
+		})
+	}
+
+	// hs256 tokens signed with the rsa public key are not verified by the rsa keys
+	pub, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	assert.NoError(t, err)
+	pub = pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pub})
+	_, err = v.Verify(sign(t, AlgorithmHS256, "rsa-1", claims(nil), pub))
+	assert.ErrorIs(t, err, ErrTokenSignature)
+}
@@ -0,0 +1,106 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// KeyStore api keys of the known callers
+// Authenticate - identity of the api key, returns ErrInvalidCredentials for unknown keys
+// Reload - reload the keys from their source, the current keys are kept when the reload fails
+type KeyStore interface {
+	Authenticate(key string) (Identity, error)
+	Reload() error
+}
+
+// keyFile json file holding the sha256 hashes of the api keys
+//
+//	{"keys": [{"id": "cms-importer", "hash": "<hex sha256 of the key>", "scopes": ["articles:write"]}]}
+type keyFile struct {
+	Keys []struct {
+		ID     string  `json:"id"`
+		Hash   string  `json:"hash"`
+		Scopes []Scope `json:"scopes"`
+	} `json:"keys"`
+}
+
+type fileKeyStore struct {
+	lock *sync.RWMutex
+	path string
+	keys map[string]Identity
+}
+
+// NewFileKeyStore create a key store loading the hashed keys from the json file at the path
+func NewFileKeyStore(path string) (KeyStore, error) {
+	s := &fileKeyStore{
+		lock: &sync.RWMutex{},
+		path: path,
+	}
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// HashKey hex encoded sha256 hash of the api key as stored in the key file
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// Authenticate identity of the key by its hash
+func (s *fileKeyStore) Authenticate(key string) (Identity, error) {
+	if key == "" {
+		return Identity{}, ErrMissingCredentials
+	}
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	identity, ok := s.keys[HashKey(key)]
+	if !ok {
+		return Identity{}, ErrInvalidCredentials
+	}
+	return identity, nil
+}
+
+// Reload read and validate the key file, replacing the keys only when the whole file is valid
+func (s *fileKeyStore) Reload() error {
+	byt, err := os.ReadFile(s.path)
+	if err != nil {
+		return fmt.Errorf("error reading api key file [%s] due to, %w", s.path, err)
+	}
+	var file keyFile
+	if err = json.Unmarshal(byt, &file); err != nil {
+		return fmt.Errorf("error decoding api key file [%s] due to, %w", s.path, err)
+	}
+
+	keys := make(map[string]Identity, len(file.Keys))
+	for i, k := range file.Keys {
+		if k.ID == "" {
+			return fmt.Errorf("api key at index [%d] has no id", i)
+		}
+		hash := strings.ToLower(k.Hash)
+		if b, err := hex.DecodeString(hash); err != nil || len(b) != sha256.Size {
+			return fmt.Errorf("api key [%s] hash must be a hex encoded sha256 hash", k.ID)
+		}
+		if _, ok := keys[hash]; ok {
+			return fmt.Errorf("api key [%s] hash is duplicated", k.ID)
+		}
+		for _, scope := range k.Scopes {
+			if !validScope(scope) {
+				return fmt.Errorf("api key [%s] has an unknown scope [%s]", k.ID, scope)
+			}
+		}
+		keys[hash] = Identity{ID: k.ID, Scopes: k.Scopes}
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.keys = keys
+	return nil
+}
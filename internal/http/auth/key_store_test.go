@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileKeyStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	writeKeys := func(content string) {
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+	writeKeys(fmt.Sprintf(`{"keys":[{"id":"reader","hash":"%s","scopes":["articles:read"]}]}`, HashKey("secret")))
+
+	s, err := NewFileKeyStore(path)
+	assert.NoError(t, err)
+
+	identity, err := s.Authenticate("secret")
+	assert.NoError(t, err)
+	assert.Equal(t, "reader", identity.ID)
+	assert.True(t, identity.HasScope(ScopeRead))
+	assert.False(t, identity.HasScope(ScopeWrite))
+
+	_, err = s.Authenticate("other")
+	assert.Equal(t, ErrInvalidCredentials, err)
+	_, err = s.Authenticate("")
+	assert.Equal(t, ErrMissingCredentials, err)
+
+	// reload picks up rotated keys
+	writeKeys(fmt.Sprintf(`{"keys":[{"id":"admin","hash":"%s","scopes":["admin"]}]}`, HashKey("rotated")))
+	assert.NoError(t, s.Reload())
+	_, err = s.Authenticate("secret")
+	assert.Equal(t, ErrInvalidCredentials, err)
+	identity, err = s.Authenticate("rotated")
+	assert.NoError(t, err)
+	assert.True(t, identity.HasScope(ScopeWrite))
+
+	// invalid files keep the current keys
+	writeKeys(`{"keys":[{"id":"broken","hash":"not-a-hash","scopes":["admin"]}]}`)
+	assert.Error(t, s.Reload())
+	_, err = s.Authenticate("rotated")
+	assert.NoError(t, err)
+
+	writeKeys(fmt.Sprintf(`{"keys":[{"id":"writer","hash":"%s","scopes":["articles:delete"]}]}`, HashKey("k")))
+	assert.Error(t, s.Reload())
+}
@@ -1,14 +1,25 @@
 package http
 
 import (
+	"article-dispatcher/internal/http/handlers"
+	"article-dispatcher/internal/http/ratelimit"
+
 	"github.com/caarlos0/env/v6"
 
+	"compress/gzip"
 	"log"
 	"time"
 )
 
 var Config RouterConfig
 
+// access log outputs
+const (
+	AccessLogStdout = "stdout"
+	AccessLogStderr = "stderr"
+	AccessLogFile   = "file"
+)
+
 type RouterConfig struct {
 	Host     string `env:"HTTP_SERVER_HOST" envDefault:"8888"`
 	Timeouts struct {
@@ -17,6 +28,70 @@ type RouterConfig struct {
 		Idle          time.Duration `env:"HTTP_SERVER_IDLE_TIMEOUT" envDefault:"10s"`
 		ShoutDownWait time.Duration `env:"HTTP_SERVER_SHOUT_DOWN_WAIT" envDefault:"5s"`
 	}
+	CacheControl struct {
+		Article string `env:"HTTP_CACHE_CONTROL_ARTICLE" envDefault:"public, max-age=60"`
+		Tags    string `env:"HTTP_CACHE_CONTROL_TAGS" envDefault:"public, max-age=30"`
+	}
+	Compression struct {
+		Enabled      bool     `env:"HTTP_COMPRESSION_ENABLED" envDefault:"true"`
+		Level        int      `env:"HTTP_COMPRESSION_LEVEL" envDefault:"-1"`
+		MinSize      int      `env:"HTTP_COMPRESSION_MIN_SIZE" envDefault:"1024"`
+		ContentTypes []string `env:"HTTP_COMPRESSION_CONTENT_TYPES" envDefault:"application/json,application/problem+json,application/xml,text/csv,text/plain"` //nolint:lll // env defaults cannot be wrapped
+	}
+	Decoding struct {
+		MaxBodySize int64 `env:"HTTP_MAX_BODY_SIZE" envDefault:"1048576"`
+		Strict      bool  `env:"HTTP_STRICT_DECODING" envDefault:"false"`
+	}
+	Problem struct {
+		TypeBase string `env:"HTTP_PROBLEM_TYPE_BASE" envDefault:"/errors/"`
+	}
+	Batch struct {
+		MaxIDs int `env:"HTTP_BATCH_MAX_IDS" envDefault:"100"`
+	}
+	Idempotency struct {
+		TTL time.Duration `env:"HTTP_IDEMPOTENCY_TTL" envDefault:"24h"`
+	}
+	List struct {
+		DefaultLimit int `env:"HTTP_LIST_DEFAULT_LIMIT" envDefault:"20"`
+		MaxLimit     int `env:"HTTP_LIST_MAX_LIMIT" envDefault:"100"`
+	}
+	Auth struct {
+		Enabled  bool   `env:"HTTP_AUTH_ENABLED" envDefault:"false"`
+		KeysFile string `env:"HTTP_AUTH_KEYS_FILE"`
+		Header   string `env:"HTTP_AUTH_HEADER" envDefault:"X-API-Key"`
+	}
+	RateLimit struct {
+		Enabled bool     `env:"HTTP_RATE_LIMIT_ENABLED" envDefault:"false"`
+		IP      string   `env:"HTTP_RATE_LIMIT_IP" envDefault:"1200/1m"`
+		Default string   `env:"HTTP_RATE_LIMIT_DEFAULT" envDefault:"600/1m"`
+		Routes  []string `env:"HTTP_RATE_LIMIT_ROUTES" envDefault:"create_article=60/1m,put_article=60/1m"`
+	}
+	CORS struct {
+		Enabled          bool          `env:"HTTP_CORS_ENABLED" envDefault:"false"`
+		AllowedOrigins   []string      `env:"HTTP_CORS_ALLOWED_ORIGINS"`
+		AllowedMethods   []string      `env:"HTTP_CORS_ALLOWED_METHODS" envDefault:"GET,POST,PUT"`
+		AllowedHeaders   []string      `env:"HTTP_CORS_ALLOWED_HEADERS" envDefault:"Authorization,Content-Type,If-Match,If-None-Match,Idempotency-Key,X-API-Key,X-Request-ID,traceparent,tracestate"`   //nolint:lll // env defaults cannot be wrapped
+		ExposedHeaders   []string      `env:"HTTP_CORS_EXPOSED_HEADERS" envDefault:"ETag,Location,RateLimit-Limit,RateLimit-Remaining,RateLimit-Reset,Retry-After,X-Request-ID,traceparent,tracestate"` //nolint:lll // env defaults cannot be wrapped
+		AllowCredentials bool          `env:"HTTP_CORS_ALLOW_CREDENTIALS" envDefault:"false"`
+		MaxAge           time.Duration `env:"HTTP_CORS_MAX_AGE" envDefault:"10m"`
+	}
+	JWT struct {
+		Enabled       bool          `env:"HTTP_JWT_ENABLED" envDefault:"false"`
+		Secret        string        `env:"HTTP_JWT_HS256_SECRET"`
+		PublicKeyFile string        `env:"HTTP_JWT_RS256_PUBLIC_KEY_FILE"`
+		JWKSFile      string        `env:"HTTP_JWT_JWKS_FILE"`
+		Issuer        string        `env:"HTTP_JWT_ISSUER"`
+		Audience      string        `env:"HTTP_JWT_AUDIENCE"`
+		Leeway        time.Duration `env:"HTTP_JWT_LEEWAY" envDefault:"30s"`
+	}
+	AccessLog struct {
+		Enabled       bool          `env:"HTTP_ACCESS_LOG_ENABLED" envDefault:"false"`
+		Format        string        `env:"HTTP_ACCESS_LOG_FORMAT" envDefault:"combined"`
+		Output        string        `env:"HTTP_ACCESS_LOG_OUTPUT" envDefault:"stdout"`
+		File          string        `env:"HTTP_ACCESS_LOG_FILE" envDefault:"logs/access.log"`
+		SampleRate    float64       `env:"HTTP_ACCESS_LOG_SAMPLE_RATE" envDefault:"1"`
+		SlowThreshold time.Duration `env:"HTTP_ACCESS_LOG_SLOW_THRESHOLD" envDefault:"1s"`
+	}
 }
 
 // Register router configurations
@@ -34,11 +109,63 @@ func (r *RouterConfig) Validate() error {
 	if Config.Host == "" {
 		log.Fatal("application http port cannot be empty")
 	}
+	if Config.Compression.Level < gzip.HuffmanOnly || Config.Compression.Level > gzip.BestCompression {
+		log.Fatal("compression level must be between -2 and 9")
+	}
+	if Config.Auth.Enabled && (Config.Auth.KeysFile == "" || Config.Auth.Header == "") {
+		log.Fatal("api key file and header are required when authentication is enabled")
+	}
+	if Config.RateLimit.Enabled {
+		if _, err := ratelimit.ParseLimit(Config.RateLimit.IP); err != nil {
+			log.Fatal(err)
+		}
+		if _, err := ratelimit.ParseLimit(Config.RateLimit.Default); err != nil {
+			log.Fatal(err)
+		}
+		if _, err := ratelimit.ParseRouteLimits(Config.RateLimit.Routes); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if Config.CORS.Enabled && len(Config.CORS.AllowedOrigins) == 0 {
+		log.Fatal("allowed origins are required when cors is enabled")
+	}
+	for _, origin := range Config.CORS.AllowedOrigins {
+		if Config.CORS.AllowCredentials && origin == "*" {
+			log.Fatal("cors credentials cannot be allowed for any origin")
+		}
+	}
+	if Config.JWT.Enabled && Config.JWT.Secret == "" && Config.JWT.PublicKeyFile == "" && Config.JWT.JWKSFile == "" {
+		log.Fatal("a jwt secret, public key or jwks file is required when jwt authentication is enabled")
+	}
+	if Config.AccessLog.Enabled {
+		switch Config.AccessLog.Format {
+		case handlers.AccessLogCommon, handlers.AccessLogCombined, handlers.AccessLogJSON:
+		default:
+			log.Fatal("access log format must be common, combined or json")
+		}
+		switch Config.AccessLog.Output {
+		case AccessLogStdout, AccessLogStderr:
+		case AccessLogFile:
+			if Config.AccessLog.File == "" {
+				log.Fatal("access log file is required when the access log is written to a file")
+			}
+		default:
+			log.Fatal("access log output must be stdout, stderr or file")
+		}
+		if Config.AccessLog.SampleRate < 0 || Config.AccessLog.SampleRate > 1 {
+			log.Fatal("access log sample rate must be between 0 and 1")
+		}
+	}
 	return nil
 }
 
 // Print router configurations
 func (r *RouterConfig) Print() interface{} {
 	defer log.Println("---loading router configs---")
-	return &Config
+	// secrets are masked before printing
+	printable := Config
+	if printable.JWT.Secret != "" {
+		printable.JWT.Secret = "*****"
+	}
+	return &printable
 }
@@ -0,0 +1,131 @@
+package encoders
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strings"
+)
+
+// csvListMember member holding the list of the list-like object payloads
+const csvListMember = "articles"
+
+// csvValueSeparator separator of the array values within a csv cell
+const csvValueSeparator = "|"
+
+// CSV encoder of the `text/csv` media type for the list-like payloads, which are either arrays or objects
+// holding the list in their `articles` member, object items are written as rows with their fields as columns
+type CSV struct{}
+
+func (CSV) ContentType() string {
+	return "text/csv; charset=utf-8"
+}
+
+func (CSV) MediaTypes() []string {
+	return []string{"text/csv"}
+}
+
+func (CSV) Encode(v interface{}) ([]byte, error) {
+	value, err := toValue(v)
+	if err != nil {
+		return nil, err
+	}
+
+	items, column, ok := csvList(value)
+	if !ok {
+		return nil, fmt.Errorf("csv requires a list payload, %w", ErrUnsupportedPayload)
+	}
+
+	header := csvHeader(items, column)
+	records := [][]string{header}
+	for _, item := range items {
+		record, err := csvRecord(item, header)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+
+	buf := &bytes.Buffer{}
+	writer := csv.NewWriter(buf)
+	if err = writer.WriteAll(records); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// csvList - find the list of the payload along with the column name of the scalar items
+func csvList(value interface{}) (items []interface{}, column string, ok bool) {
+	switch v := value.(type) {
+	case []interface{}:
+		return v, "value", true
+	case object:
+		for _, mem := range v {
+			if list, isList := mem.value.([]interface{}); isList && mem.key == csvListMember {
+				return list, mem.key, true
+			}
+		}
+	}
+	return nil, "", false
+}
+
+// csvHeader - columns of the object items in the first seen order, scalar items are written into a single column
+func csvHeader(items []interface{}, column string) []string {
+	header := make([]string, 0)
+	seen := make(map[string]struct{})
+	for _, item := range items {
+		obj, ok := item.(object)
+		if !ok {
+			return []string{column}
+		}
+		for _, mem := range obj {
+			if _, ok := seen[mem.key]; !ok {
+				seen[mem.key] = struct{}{}
+				header = append(header, mem.key)
+			}
+		}
+	}
+	if len(header) == 0 {
+		header = append(header, column)
+	}
+	return header
+}
+
+// csvRecord - cells of the item in the header order, arrays of scalars are joined within a single cell
+func csvRecord(item interface{}, header []string) ([]string, error) {
+	obj, ok := item.(object)
+	if !ok {
+		cell, err := csvCell(item)
+		return []string{cell}, err
+	}
+
+	fields := make(map[string]interface{}, len(obj))
+	for _, mem := range obj {
+		fields[mem.key] = mem.value
+	}
+	record := make([]string, 0, len(header))
+	for _, column := range header {
+		cell, err := csvCell(fields[column])
+		if err != nil {
+			return nil, err
+		}
+		record = append(record, cell)
+	}
+	return record, nil
+}
+
+func csvCell(value interface{}) (string, error) {
+	arr, ok := value.([]interface{})
+	if !ok {
+		return scalarString(value)
+	}
+	cells := make([]string, 0, len(arr))
+	for _, item := range arr {
+		cell, err := scalarString(item)
+		if err != nil {
+			return "", err
+		}
+		cells = append(cells, cell)
+	}
+	return strings.Join(cells, csvValueSeparator), nil
+}
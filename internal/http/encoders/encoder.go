@@ -0,0 +1,137 @@
+package encoders
+
+import (
+	"errors"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrUnsupportedPayload the payload cannot be represented in the media type of the encoder
+var ErrUnsupportedPayload = errors.New("payload not supported by the encoder")
+
+// Encoder encode the response payloads into a media type
+// ContentType - content type of the encoded payloads
+// MediaTypes - media types of the Accept header the encoder is selected for
+// Encode - encode the payload, returns ErrUnsupportedPayload if the payload cannot be represented
+type Encoder interface {
+	ContentType() string
+	MediaTypes() []string
+	Encode(v interface{}) ([]byte, error)
+}
+
+// Registry encoders selected by the Accept header, the first encoder is the default one
+type Registry struct {
+	encoders []Encoder
+}
+
+// NewRegistry create a registry of the encoders, the first encoder is used when the Accept header is missing
+func NewRegistry(encoders ...Encoder) *Registry {
+	return &Registry{encoders: encoders}
+}
+
+// DefaultRegistry registry of the JSON, XML, CSV and MessagePack encoders with JSON as the default
+func DefaultRegistry() *Registry {
+	return NewRegistry(JSON{}, XML{}, CSV{}, MessagePack{})
+}
+
+// Default the encoder used when the Accept header is missing
+func (r *Registry) Default() Encoder {
+	return r.encoders[0]
+}
+
+type acceptRange struct {
+	mediaType string
+	quality   float64
+}
+
+// Negotiate the encoders acceptable for the Accept header ordered by the client preference, empty when none of
+// the encoders are acceptable
+func (r *Registry) Negotiate(accept string) []Encoder {
+	if strings.TrimSpace(accept) == "" {
+		return []Encoder{r.Default()}
+	}
+
+	ranges := parseAccept(accept)
+	negotiated := make([]Encoder, 0, len(r.encoders))
+	added := make(map[Encoder]struct{}, len(r.encoders))
+	for _, ar := range ranges {
+		if ar.quality <= 0 {
+			continue
+		}
+		for _, encoder := range r.encoders {
+			if _, ok := added[encoder]; ok || !matchRange(ar.mediaType, encoder) || excluded(ranges, encoder) {
+				continue
+			}
+			added[encoder] = struct{}{}
+			negotiated = append(negotiated, encoder)
+		}
+	}
+	return negotiated
+}
+
+// Accepts check whether the Accept header explicitly accepts the media type, wildcard ranges are not matched
+func Accepts(accept, mediaType string) bool {
+	for _, ar := range parseAccept(accept) {
+		if ar.mediaType == mediaType && ar.quality > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// parseAccept - parse the media ranges of the Accept header ordered by quality, then by specificity
+func parseAccept(accept string) []acceptRange {
+	ranges := make([]acceptRange, 0)
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(q, 64); err == nil {
+				quality = parsed
+			}
+		}
+		ranges = append(ranges, acceptRange{mediaType: mediaType, quality: quality})
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		if ranges[i].quality != ranges[j].quality {
+			return ranges[i].quality > ranges[j].quality
+		}
+		return strings.Count(ranges[i].mediaType, "*") < strings.Count(ranges[j].mediaType, "*")
+	})
+	return ranges
+}
+
+// matchRange - match the media range, including the `*/*` and `type/*` wildcards, with the encoder media types
+func matchRange(mediaRange string, encoder Encoder) bool {
+	for _, mediaType := range encoder.MediaTypes() {
+		if mediaRange == "*/*" || mediaRange == mediaType {
+			return true
+		}
+		if strings.HasSuffix(mediaRange, "/*") &&
+			strings.HasPrefix(mediaType, strings.TrimSuffix(mediaRange, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
+// excluded - check whether one of the media types of the encoder is explicitly excluded with `q=0`
+func excluded(ranges []acceptRange, encoder Encoder) bool {
+	for _, ar := range ranges {
+		if ar.quality > 0 || strings.Contains(ar.mediaType, "*") {
+			continue
+		}
+		for _, mediaType := range encoder.MediaTypes() {
+			if ar.mediaType == mediaType {
+				return true
+			}
+		}
+	}
+	return false
+}
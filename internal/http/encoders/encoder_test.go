@@ -0,0 +1,86 @@
+package encoders
+
+import (
+	"article-dispatcher/internal/domain/models"
+
+	"github.com/stretchr/testify/assert"
+
+	"errors"
+	"testing"
+)
+
+func TestRegistry_Negotiate(t *testing.T) {
+	registry := DefaultRegistry()
+	tests := []struct {
+		name   string
+		accept string
+		want   []Encoder
+	}{
+		{name: "missing_accept", accept: "", want: []Encoder{JSON{}}},
+		{name: "any", accept: "*/*", want: []Encoder{JSON{}, XML{}, CSV{}, MessagePack{}}},
+		{name: "quality_order", accept: "application/json;q=0.5, text/csv", want: []Encoder{CSV{}, JSON{}}},
+		{name: "type_wildcard", accept: "text/*", want: []Encoder{XML{}, CSV{}}},
+		{name: "excluded", accept: "*/*, application/json;q=0", want: []Encoder{XML{}, CSV{}, MessagePack{}}},
+		{name: "msgpack_alias", accept: "application/x-msgpack", want: []Encoder{MessagePack{}}},
+		{name: "not_acceptable", accept: "image/png", want: []Encoder{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, registry.Negotiate(tt.accept))
+		})
+	}
+}
+
+func TestAccepts(t *testing.T) {
+	assert.True(t, Accepts("application/problem+json", "application/problem+json"))
+	assert.True(t, Accepts("application/json, application/problem+json;q=0.5", "application/problem+json"))
+	assert.False(t, Accepts("application/problem+json;q=0", "application/problem+json"))
+	assert.False(t, Accepts("*/*", "application/problem+json"))
+	assert.False(t, Accepts("application/*", "application/problem+json"))
+	assert.False(t, Accepts("", "application/problem+json"))
+}
+
+func TestEncoders(t *testing.T) {
+	page := models.ArticlePage{
+		Articles: models.Articles{
+			{Id: "1", Title: "a, b", Date: "2023-03-30", Body: "<b>", Tags: []string{"fun", "health"}, Version: 1},
+		},
+	}
+
+	body, err := XML{}.Encode(page)
+	assert.NoError(t, err)
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>`+"\n"+
+		`<response><articles><item><id>1</id><title>a, b</title><date>2023-03-30</date><body>&lt;b&gt;</body>`+
+		`<tags><item>fun</item><item>health</item></tags><version>1</version></item></articles></response>`,
+		string(body))
+
+	body, err = CSV{}.Encode(page)
+	assert.NoError(t, err)
+	assert.Equal(t, "id,title,date,body,tags,version\n1,\"a, b\",2023-03-30,<b>,fun|health,1\n", string(body))
+
+	body, err = CSV{}.Encode(models.TaggedArticles{Tag: "fun", Articles: []string{"1", "2"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "articles\n1\n2\n", string(body))
+
+	_, err = CSV{}.Encode(models.Article{Id: "1"})
+	assert.True(t, errors.Is(err, ErrUnsupportedPayload))
+
+	body, err = MessagePack{}.Encode(struct {
+		ID    string   `json:"id"`
+		Count int      `json:"count"`
+		Tags  []string `json:"tags"`
+		Ok    bool     `json:"ok"`
+		Large int      `json:"large"`
+		Neg   int      `json:"neg"`
+	}{ID: "1", Count: 3, Tags: []string{"a"}, Ok: true, Large: 300, Neg: -5})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{
+		0x86,
+		0xa2, 'i', 'd', 0xa1, '1',
+		0xa5, 'c', 'o', 'u', 'n', 't', 0x03,
+		0xa4, 't', 'a', 'g', 's', 0x91, 0xa1, 'a',
+		0xa2, 'o', 'k', 0xc3,
+		0xa5, 'l', 'a', 'r', 'g', 'e', 0xd1, 0x01, 0x2c,
+		0xa3, 'n', 'e', 'g', 0xfb,
+	}, body)
+}
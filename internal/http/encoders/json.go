@@ -0,0 +1,18 @@
+package encoders
+
+import "encoding/json"
+
+// JSON encoder of the `application/json` media type
+type JSON struct{}
+
+func (JSON) ContentType() string {
+	return "application/json"
+}
+
+func (JSON) MediaTypes() []string {
+	return []string{"application/json"}
+}
+
+func (JSON) Encode(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
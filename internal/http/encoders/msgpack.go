@@ -0,0 +1,124 @@
+package encoders
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// MessagePack encoder of the `application/msgpack` media type
+type MessagePack struct{}
+
+func (MessagePack) ContentType() string {
+	return "application/msgpack"
+}
+
+func (MessagePack) MediaTypes() []string {
+	return []string{"application/msgpack", "application/x-msgpack", "application/vnd.msgpack"}
+}
+
+func (MessagePack) Encode(v interface{}) ([]byte, error) {
+	value, err := toValue(v)
+	if err != nil {
+		return nil, err
+	}
+	buf := &bytes.Buffer{}
+	if err = encodeMsgPack(buf, value); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// nolint:gomnd
+func encodeMsgPack(buf *bytes.Buffer, value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		buf.WriteByte(0xc0)
+	case bool:
+		if v {
+			buf.WriteByte(0xc3)
+		} else {
+			buf.WriteByte(0xc2)
+		}
+	case json.Number:
+		return encodeMsgPackNumber(buf, v)
+	case string:
+		encodeMsgPackLength(buf, len(v), 0xa0, 31, 0xd9, 0xda, 0xdb)
+		buf.WriteString(v)
+	case []interface{}:
+		encodeMsgPackLength(buf, len(v), 0x90, 15, 0, 0xdc, 0xdd)
+		for _, item := range v {
+			if err := encodeMsgPack(buf, item); err != nil {
+				return err
+			}
+		}
+	case object:
+		encodeMsgPackLength(buf, len(v), 0x80, 15, 0, 0xde, 0xdf)
+		for _, mem := range v {
+			if err := encodeMsgPack(buf, mem.key); err != nil {
+				return err
+			}
+			if err := encodeMsgPack(buf, mem.value); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unexpected value type [%T]", value)
+	}
+	return nil
+}
+
+// encodeMsgPackLength - write the header of a string, array or map of the length with the fix, 8, 16 and 32 bit
+// formats, formats without an 8 bit variant pass zero
+// nolint:gomnd
+func encodeMsgPackLength(buf *bytes.Buffer, length int, fix byte, fixMax int, f8, f16, f32 byte) {
+	switch {
+	case length <= fixMax:
+		buf.WriteByte(fix | byte(length))
+	case f8 != 0 && length <= math.MaxUint8:
+		buf.WriteByte(f8)
+		buf.WriteByte(byte(length))
+	case length <= math.MaxUint16:
+		buf.WriteByte(f16)
+		_ = binary.Write(buf, binary.BigEndian, uint16(length))
+	default:
+		buf.WriteByte(f32)
+		_ = binary.Write(buf, binary.BigEndian, uint32(length))
+	}
+}
+
+// encodeMsgPackNumber - write integers with the smallest integer format and the others as 64 bit floats
+// nolint:gomnd
+func encodeMsgPackNumber(buf *bytes.Buffer, number json.Number) error {
+	i, err := number.Int64()
+	if err != nil {
+		f, err := number.Float64()
+		if err != nil {
+			return err
+		}
+		buf.WriteByte(0xcb)
+		return binary.Write(buf, binary.BigEndian, f)
+	}
+
+	switch {
+	case i >= 0 && i <= math.MaxInt8:
+		buf.WriteByte(byte(i))
+	case i < 0 && i >= -32:
+		buf.WriteByte(byte(int8(i)))
+	case i >= math.MinInt8 && i <= math.MaxInt8:
+		buf.WriteByte(0xd0)
+		buf.WriteByte(byte(int8(i)))
+	case i >= math.MinInt16 && i <= math.MaxInt16:
+		buf.WriteByte(0xd1)
+		return binary.Write(buf, binary.BigEndian, int16(i))
+	case i >= math.MinInt32 && i <= math.MaxInt32:
+		buf.WriteByte(0xd2)
+		return binary.Write(buf, binary.BigEndian, int32(i))
+	default:
+		buf.WriteByte(0xd3)
+		return binary.Write(buf, binary.BigEndian, i)
+	}
+	return nil
+}
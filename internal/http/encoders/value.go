@@ -0,0 +1,106 @@
+package encoders
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// object json object keeping the order of the members
+type object []member
+
+type member struct {
+	key   string
+	value interface{}
+}
+
+// toValue - convert the payload into a generic value through its json representation, objects are decoded into
+// ordered objects so that the encoders keep the json field order, numbers are kept as json.Number
+func toValue(v interface{}) (interface{}, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	return decodeValue(decoder)
+}
+
+func decodeValue(decoder *json.Decoder) (interface{}, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return token, nil
+	}
+	switch delim {
+	case '{':
+		obj := make(object, 0)
+		for decoder.More() {
+			keyToken, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			obj = append(obj, member{key: fmt.Sprint(keyToken), value: value})
+		}
+		_, err = decoder.Token()
+		return obj, err
+	case '[':
+		arr := make([]interface{}, 0)
+		for decoder.More() {
+			value, err := decodeValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, value)
+		}
+		_, err = decoder.Token()
+		return arr, err
+	default:
+		return nil, fmt.Errorf("unexpected json delimiter [%s]", delim)
+	}
+}
+
+// scalarString - string form of a scalar value, nested values are written as json
+func scalarString(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return fmt.Sprintf("%t", v), nil
+	default:
+		raw, err := json.Marshal(toInterface(v))
+		return string(raw), err
+	}
+}
+
+// toInterface - convert the ordered value back into the plain json value
+func toInterface(value interface{}) interface{} {
+	switch v := value.(type) {
+	case object:
+		m := make(map[string]interface{}, len(v))
+		for _, mem := range v {
+			m[mem.key] = toInterface(mem.value)
+		}
+		return m
+	case []interface{}:
+		arr := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			arr = append(arr, toInterface(item))
+		}
+		return arr
+	default:
+		return v
+	}
+}
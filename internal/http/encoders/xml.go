@@ -0,0 +1,72 @@
+package encoders
+
+import (
+	"bytes"
+	"encoding/xml"
+)
+
+const (
+	xmlRootElement = "response"
+	xmlItemElement = "item"
+)
+
+// XML encoder of the `application/xml` media type, the payload is written under a `response` root element with
+// the json field names as the element names and the array items as `item` elements
+type XML struct{}
+
+func (XML) ContentType() string {
+	return "application/xml"
+}
+
+func (XML) MediaTypes() []string {
+	return []string{"application/xml", "text/xml"}
+}
+
+func (XML) Encode(v interface{}) ([]byte, error) {
+	value, err := toValue(v)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := bytes.NewBufferString(xml.Header)
+	encoder := xml.NewEncoder(buf)
+	if err = encodeXMLElement(encoder, xmlRootElement, value); err != nil {
+		return nil, err
+	}
+	if err = encoder.Flush(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func encodeXMLElement(encoder *xml.Encoder, name string, value interface{}) error {
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	if err := encoder.EncodeToken(start); err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case object:
+		for _, mem := range v {
+			if err := encodeXMLElement(encoder, mem.key, mem.value); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if err := encodeXMLElement(encoder, xmlItemElement, item); err != nil {
+				return err
+			}
+		}
+	default:
+		text, err := scalarString(v)
+		if err != nil {
+			return err
+		}
+		if err = encoder.EncodeToken(xml.CharData(text)); err != nil {
+			return err
+		}
+	}
+
+	return encoder.EncodeToken(start.End())
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"article-dispatcher/internal/pkg/log"
+	"article-dispatcher/internal/pkg/tracing"
+
+	"bytes"
+	"encoding/json"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// access log formats
+const (
+	AccessLogCommon   = "common"
+	AccessLogCombined = "combined"
+	AccessLogJSON     = "json"
+)
+
+// AccessLogMiddleware writes an entry of every request with its status, size and latency to the sink. Successful
+// requests are sampled at SampleRate, while failed requests and requests slower than SlowThreshold are always logged.
+// The common and combined entries are followed by the trace id and the duration in microseconds
+type AccessLogMiddleware struct {
+	Sink   log.Sink
+	Format string
+	// SampleRate fraction of the successful requests logged, between 0 and 1
+	SampleRate float64
+	// SlowThreshold duration above which requests are always logged, zero disables it
+	SlowThreshold time.Duration
+}
+
+// accessEntry json access log entry
+type accessEntry struct {
+	Time       string `json:"ts"`
+	TraceID    string `json:"trace_id"`
+	RemoteAddr string `json:"remote_addr"`
+	Method     string `json:"method"`
+	URI        string `json:"uri"`
+	Proto      string `json:"proto"`
+	Status     int    `json:"status"`
+	Bytes      int64  `json:"bytes"`
+	Duration   int64  `json:"duration_us"`
+	Referer    string `json:"referer,omitempty"`
+	UserAgent  string `json:"user_agent,omitempty"`
+	Slow       bool   `json:"slow,omitempty"`
+}
+
+func (am AccessLogMiddleware) MiddleFunc(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		start := time.Now()
+		recorder := newStatusRecorder(writer)
+		handler.ServeHTTP(recorder, request)
+		duration := time.Since(start)
+
+		slow := am.SlowThreshold > 0 && duration > am.SlowThreshold
+		failed := recorder.status >= http.StatusBadRequest
+		// nolint:gosec // sampling does not need a secure source
+		if !failed && !slow && am.SampleRate < 1 && rand.Float64() >= am.SampleRate {
+			return
+		}
+
+		level := log.INFO
+		switch {
+		case recorder.status >= http.StatusInternalServerError:
+			level = log.ERROR
+		case failed || slow:
+			level = log.WARN
+		}
+		_ = am.Sink.Write(level, am.entry(request, recorder, start, duration, slow))
+	})
+}
+
+// entry access log entry of the request in the format of the middleware
+func (am AccessLogMiddleware) entry(request *http.Request, recorder *statusRecorder, start time.Time,
+	duration time.Duration, slow bool) []byte {
+	remoteAddr, _, err := net.SplitHostPort(request.RemoteAddr)
+	if err != nil {
+		remoteAddr = request.RemoteAddr
+	}
+	// the trace middleware echoes the request id, it does not run for requests matching no route
+	traceID := recorder.Header().Get(tracing.HeaderRequestID)
+	if traceID == "" && tracing.ValidRequestID(request.Header.Get(tracing.HeaderRequestID)) {
+		traceID = request.Header.Get(tracing.HeaderRequestID)
+	}
+
+	if am.Format == AccessLogJSON {
+		b, _ := json.Marshal(accessEntry{
+			Time:       start.UTC().Format(time.RFC3339Nano),
+			TraceID:    traceID,
+			RemoteAddr: remoteAddr,
+			Method:     request.Method,
+			URI:        request.RequestURI,
+			Proto:      request.Proto,
+			Status:     recorder.status,
+			Bytes:      recorder.bytes,
+			Duration:   duration.Microseconds(),
+			Referer:    request.Referer(),
+			UserAgent:  request.UserAgent(),
+			Slow:       slow,
+		})
+		return append(b, '\n')
+	}
+
+	// host ident authuser [time] "request" status bytes
+	var b bytes.Buffer
+	b.WriteString(orDash(remoteAddr))
+	b.WriteString(" - - [")
+	b.WriteString(start.Format("02/Jan/2006:15:04:05 -0700"))
+	b.WriteString("] ")
+	b.WriteString(strconv.Quote(request.Method + " " + request.RequestURI + " " + request.Proto))
+	b.WriteByte(' ')
+	b.WriteString(strconv.Itoa(recorder.status))
+	b.WriteByte(' ')
+	if recorder.bytes == 0 {
+		b.WriteByte('-')
+	} else {
+		b.WriteString(strconv.FormatInt(recorder.bytes, 10))
+	}
+	if am.Format == AccessLogCombined {
+		b.WriteByte(' ')
+		b.WriteString(strconv.Quote(orDash(request.Referer())))
+		b.WriteByte(' ')
+		b.WriteString(strconv.Quote(orDash(request.UserAgent())))
+	}
+	b.WriteByte(' ')
+	b.WriteString(orDash(traceID))
+	b.WriteByte(' ')
+	b.WriteString(strconv.FormatInt(duration.Microseconds(), 10))
+	b.WriteByte('\n')
+	return b.Bytes()
+}
+
+// orDash the value, otherwise `-` for missing values
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
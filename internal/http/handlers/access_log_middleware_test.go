@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"article-dispatcher/internal/pkg/log"
+	"article-dispatcher/internal/pkg/tracing"
+
+	"github.com/stretchr/testify/assert"
+
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+)
+
+// recordingSink sink recording the levels and the entries written
+type recordingSink struct {
+	levels  []string
+	entries []string
+}
+
+func (rs *recordingSink) Write(level string, entry []byte) error {
+	rs.levels = append(rs.levels, level)
+	rs.entries = append(rs.entries, string(entry))
+	return nil
+}
+
+func (rs *recordingSink) Close() error {
+	return nil
+}
+
+// statusHandler handler answering the status with the body, echoing the request id as the trace middleware
+func statusHandler(status int, body string, delay time.Duration) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		time.Sleep(delay)
+		if id := request.Header.Get("X-Echo-Request-ID"); id != "" {
+			writer.Header().Set(tracing.HeaderRequestID, id)
+		}
+		writer.WriteHeader(status)
+		_, _ = writer.Write([]byte(body))
+	})
+}
+
+// accessRequest request of the access log tests
+func accessRequest() *http.Request {
+	request := httptest.NewRequest(http.MethodGet, "/articles/1?fields=title", nil)
+	request.RemoteAddr = "192.0.2.10:52000"
+	request.Header.Set("User-Agent", "tester/1.0")
+	request.Header.Set("Referer", "https://example.com/")
+	request.Header.Set("X-Echo-Request-ID", "req-1")
+	return request
+}
+
+func TestAccessLogMiddleware_Formats(t *testing.T) {
+	tests := []struct {
+		format string
+		want   *regexp.Regexp
+	}{
+		{
+			format: AccessLogCommon,
+			want: regexp.MustCompile(`^192\.0\.2\.10 - - \[\d{2}/\w{3}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4}] ` +
+				`"GET /articles/1\?fields=title HTTP/1\.1" 200 11 req-1 \d+\n$`),
+		},
+		{
+			format: AccessLogCombined,
+			want: regexp.MustCompile(`^192\.0\.2\.10 - - \[[^]]+] "GET /articles/1\?fields=title HTTP/1\.1" 200 11 ` +
+				`"https://example\.com/" "tester/1\.0" req-1 \d+\n$`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			sink := &recordingSink{}
+			am := AccessLogMiddleware{Sink: sink, Format: tt.format, SampleRate: 1}
+			am.MiddleFunc(statusHandler(http.StatusOK, `{"id":"1"}`+"\n", 0)).ServeHTTP(httptest.NewRecorder(), accessRequest())
+			assert.Equal(t, []string{log.INFO}, sink.levels)
+			if assert.Len(t, sink.entries, 1) {
+				assert.Regexp(t, tt.want, sink.entries[0])
+			}
+		})
+	}
+
+	t.Run(AccessLogJSON, func(t *testing.T) {
+		sink := &recordingSink{}
+		am := AccessLogMiddleware{Sink: sink, Format: AccessLogJSON, SampleRate: 1}
+		am.MiddleFunc(statusHandler(http.StatusOK, `{"id":"1"}`+"\n", 0)).ServeHTTP(httptest.NewRecorder(), accessRequest())
+		if !assert.Len(t, sink.entries, 1) {
+			return
+		}
+		var entry accessEntry
+		assert.NoError(t, json.Unmarshal([]byte(sink.entries[0]), &entry))
+		_, err := time.Parse(time.RFC3339Nano, entry.Time)
+		assert.NoError(t, err)
+		entry.Time, entry.Duration = "", 0
+		assert.Equal(t, accessEntry{
+			TraceID:    "req-1",
+			RemoteAddr: "192.0.2.10",
+			Method:     http.MethodGet,
+			URI:        "/articles/1?fields=title",
+			Proto:      "HTTP/1.1",
+			Status:     http.StatusOK,
+			Bytes:      11,
+			Referer:    "https://example.com/",
+			UserAgent:  "tester/1.0",
+		}, entry)
+	})
+
+	// empty bodies and missing headers are written as `-`
+	t.Run("missing_values", func(t *testing.T) {
+		sink := &recordingSink{}
+		am := AccessLogMiddleware{Sink: sink, Format: AccessLogCombined, SampleRate: 1}
+		request := httptest.NewRequest(http.MethodHead, "/articles/1", nil)
+		request.RemoteAddr = "192.0.2.10:52000"
+		am.MiddleFunc(statusHandler(http.StatusNoContent, "", 0)).ServeHTTP(httptest.NewRecorder(), request)
+		if assert.Len(t, sink.entries, 1) {
+			assert.Regexp(t, `"HEAD /articles/1 HTTP/1\.1" 204 - "-" "-" - \d+\n$`, sink.entries[0])
+		}
+	})
+}
+
+func TestAccessLogMiddleware_TraceID(t *testing.T) {
+	tests := []struct {
+		name     string
+		echoed   string
+		inbound  string
+		wantJSON string
+	}{
+		{name: "echoed_request_id", echoed: "req-1", inbound: "other", wantJSON: `"trace_id":"req-1"`},
+		// requests matching no route do not reach the trace middleware
+		{name: "inbound_request_id", inbound: "req-2", wantJSON: `"trace_id":"req-2"`},
+		{name: "invalid_inbound_request_id", inbound: "req 3", wantJSON: `"trace_id":""`},
+		{name: "no_request_id", wantJSON: `"trace_id":""`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &recordingSink{}
+			am := AccessLogMiddleware{Sink: sink, Format: AccessLogJSON, SampleRate: 1}
+			request := httptest.NewRequest(http.MethodGet, "/nope", nil)
+			request.Header.Set("X-Echo-Request-ID", tt.echoed)
+			request.Header.Set(tracing.HeaderRequestID, tt.inbound)
+			am.MiddleFunc(statusHandler(http.StatusNotFound, "", 0)).ServeHTTP(httptest.NewRecorder(), request)
+			if assert.Len(t, sink.entries, 1) {
+				assert.Contains(t, sink.entries[0], tt.wantJSON)
+			}
+		})
+	}
+}
+
+func TestAccessLogMiddleware_Sampling(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		sampleRate    float64
+		slowThreshold time.Duration
+		delay         time.Duration
+		wantLevel     string
+		wantSlow      bool
+	}{
+		{name: "success_sampled", status: http.StatusOK, sampleRate: 1, wantLevel: log.INFO},
+		{name: "success_not_sampled", status: http.StatusOK, sampleRate: 0},
+		{name: "redirect_not_sampled", status: http.StatusNotModified, sampleRate: 0},
+		{name: "client_error_always_logged", status: http.StatusNotFound, sampleRate: 0, wantLevel: log.WARN},
+		{name: "server_error_always_logged", status: http.StatusServiceUnavailable, sampleRate: 0, wantLevel: log.ERROR},
+		{
+			name: "slow_always_logged", status: http.StatusOK, sampleRate: 0,
+			slowThreshold: time.Millisecond, delay: 5 * time.Millisecond, wantLevel: log.WARN, wantSlow: true,
+		},
+		{
+			name: "slow_server_error", status: http.StatusInternalServerError, sampleRate: 0,
+			slowThreshold: time.Millisecond, delay: 5 * time.Millisecond, wantLevel: log.ERROR, wantSlow: true,
+		},
+		{
+			name: "below_threshold_not_sampled", status: http.StatusOK, sampleRate: 0,
+			slowThreshold: time.Minute, delay: time.Millisecond,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &recordingSink{}
+			am := AccessLogMiddleware{
+				Sink:          sink,
+				Format:        AccessLogJSON,
+				SampleRate:    tt.sampleRate,
+				SlowThreshold: tt.slowThreshold,
+			}
+			writer := httptest.NewRecorder()
+			am.MiddleFunc(statusHandler(tt.status, "body", tt.delay)).ServeHTTP(writer, accessRequest())
+
+			// the response is not affected by the access log
+			assert.Equal(t, tt.status, writer.Code)
+			if tt.wantLevel == "" {
+				assert.Empty(t, sink.entries)
+				return
+			}
+			assert.Equal(t, []string{tt.wantLevel}, sink.levels)
+			var entry accessEntry
+			assert.NoError(t, json.Unmarshal([]byte(sink.entries[0]), &entry))
+			assert.Equal(t, tt.status, entry.Status)
+			assert.Equal(t, tt.wantSlow, entry.Slow)
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"article-dispatcher/internal/domain/models"
+
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// maxMultipartMemory maximum bytes of the multipart form kept in memory, the rest is stored in temporary files
+const maxMultipartMemory = 10 << 20
+
+// form fields of the article submissions
+const (
+	formFieldTitle      = "title"
+	formFieldDate       = "date"
+	formFieldBody       = "body"
+	formFieldTags       = "tags"
+	formFieldSource     = "source"
+	formFieldExternalID = "external_id"
+)
+
+var formFields = []string{formFieldTitle, formFieldDate, formFieldBody, formFieldTags, formFieldSource, formFieldExternalID}
+
+// decodeArticle - decode the article from the request body chosen by the content type, requests without a
+// content type are decoded as json unless decoding is strict
+func decodeArticle(request *http.Request, strict bool) (models.Article, error) {
+	var article models.Article
+	mediaType, err := requestMediaType(request, strict)
+	if err != nil {
+		return article, err
+	}
+
+	switch {
+	case isJSON(mediaType):
+		err = decodeJSON(request, &article, strict)
+		return article, err
+	case mediaType == "application/x-www-form-urlencoded":
+		if err = request.ParseForm(); err != nil {
+			return article, bodyError(request, err, "error decoding form body")
+		}
+		if strict {
+			if err = checkFormFields(request.PostForm); err != nil {
+				return article, err
+			}
+		}
+		return formArticle(request.PostForm), nil
+	case mediaType == "multipart/form-data":
+		return decodeMultipartArticle(request, strict)
+	default:
+		return article, UnsupportedMediaType{fmt.Errorf("unsupported content type [%s]", mediaType)}
+	}
+}
+
+// decodeJSONBody - decode the json request body, other content types are rejected when decoding is strict
+func decodeJSONBody(request *http.Request, v interface{}, strict bool) error {
+	mediaType, err := requestMediaType(request, strict)
+	if err != nil {
+		return err
+	}
+	if strict && !isJSON(mediaType) {
+		return UnsupportedMediaType{fmt.Errorf("unsupported content type [%s], expected application/json", mediaType)}
+	}
+	return decodeJSON(request, v, strict)
+}
+
+// requestMediaType - media type of the request content type, the content type is required when decoding is strict
+func requestMediaType(request *http.Request, strict bool) (string, error) {
+	contentType := request.Header.Get("Content-Type")
+	if contentType == "" {
+		if strict && request.ContentLength != 0 {
+			return "", MissingContentType{fmt.Errorf("content type is required for the request body")}
+		}
+		return "", nil
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", UnsupportedMediaType{fmt.Errorf("invalid content type [%s] due to, %w", contentType, err)}
+	}
+	return mediaType, nil
+}
+
+// isJSON - check if the media type is json, an empty media type is treated as json
+func isJSON(mediaType string) bool {
+	return mediaType == "" || mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// decodeJSON - decode a single json value, strict decoding rejects unknown fields and data after the value
+func decodeJSON(request *http.Request, v interface{}, strict bool) error {
+	decoder := json.NewDecoder(request.Body)
+	if strict {
+		decoder.DisallowUnknownFields()
+	}
+	if err := decoder.Decode(v); err != nil {
+		if strict && isUnknownFieldError(err) {
+			return UnknownField{fmt.Errorf("error decoding request body due to, %w", err)}
+		}
+		return bodyError(request, err, "error decoding request body")
+	}
+	if !strict {
+		return nil
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		// the decoder may have buffered the start of a trailing value over the limit without an error
+		if bodyErr := bodyError(request, err, "error reading request body"); isPayloadTooLarge(bodyErr) {
+			return bodyErr
+		}
+		return TrailingData{fmt.Errorf("request body must contain a single json value")}
+	}
+	return nil
+}
+
+// isUnknownFieldError - check if the error is a json unknown field error, the json package does not export a type for
+// them so the message is matched
+func isUnknownFieldError(err error) bool {
+	return strings.HasPrefix(err.Error(), "json: unknown field ")
+}
+
+// isPayloadTooLarge - check if the error is a payload too large error
+func isPayloadTooLarge(err error) bool {
+	_, ok := err.(PayloadTooLarge)
+	return ok
+}
+
+// decodeMultipartArticle - decode the multipart form article, the body can either be a form field or a file part
+func decodeMultipartArticle(request *http.Request, strict bool) (models.Article, error) {
+	var article models.Article
+	if err := request.ParseMultipartForm(maxMultipartMemory); err != nil {
+		return article, bodyError(request, err, "error decoding multipart body")
+	}
+	// the server only removes the temporary files of the form of the original request, not of its copies
+	defer request.MultipartForm.RemoveAll()
+	if strict {
+		fields := url.Values{}
+		for name := range request.MultipartForm.Value {
+			fields.Add(name, "")
+		}
+		for name := range request.MultipartForm.File {
+			fields.Add(name, "")
+		}
+		if err := checkFormFields(fields); err != nil {
+			return article, err
+		}
+	}
+	article = formArticle(request.MultipartForm.Value)
+
+	files := request.MultipartForm.File[formFieldBody]
+	if len(files) == 0 {
+		return article, nil
+	}
+	file, err := files[0].Open()
+	if err != nil {
+		return article, InvalidPayload{fmt.Errorf("error opening body file part due to, %w", err)}
+	}
+	defer file.Close()
+	body, err := io.ReadAll(file)
+	if err != nil {
+		return article, InvalidPayload{fmt.Errorf("error reading body file part due to, %w", err)}
+	}
+	article.Body = string(body)
+	return article, nil
+}
+
+// checkFormFields - reject the fields which are not article fields
+func checkFormFields(form url.Values) error {
+	for name := range form {
+		known := false
+		for _, field := range formFields {
+			known = known || name == field
+		}
+		if !known {
+			return UnknownField{fmt.Errorf("unknown form field [%s]", name)}
+		}
+	}
+	return nil
+}
+
+// formArticle - article from the form fields, tags are the repeated tag fields
+func formArticle(form url.Values) models.Article {
+	return models.Article{
+		Title:      form.Get(formFieldTitle),
+		Date:       form.Get(formFieldDate),
+		Body:       form.Get(formFieldBody),
+		Tags:       form[formFieldTags],
+		Source:     form.Get(formFieldSource),
+		ExternalID: form.Get(formFieldExternalID),
+	}
+}
@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"article-dispatcher/internal/domain/models"
+
+	"github.com/stretchr/testify/assert"
+
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// multipartRequest create request with the multipart form of the fields, the body is sent as a file part when set
+func multipartRequest(t *testing.T, fields url.Values, bodyFile string) *http.Request {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	for name, values := range fields {
+		for _, value := range values {
+			assert.NoError(t, mw.WriteField(name, value))
+		}
+	}
+	if bodyFile != "" {
+		part, err := mw.CreateFormFile(formFieldBody, "body.txt")
+		assert.NoError(t, err)
+		_, err = part.Write([]byte(bodyFile))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, mw.Close())
+	request := httptest.NewRequest(http.MethodPost, "/articles", &buf)
+	request.Header.Set("Content-Type", mw.FormDataContentType())
+	return request
+}
+
+func TestDecodeArticle_Forms(t *testing.T) {
+	fields := url.Values{
+		formFieldTitle: {"test"},
+		formFieldDate:  {"2023-03-30"},
+		formFieldBody:  {"test body"},
+		formFieldTags:  {"fun", "health"},
+	}
+	want := models.Article{Title: "test", Date: "2023-03-30", Body: "test body", Tags: []string{"fun", "health"}}
+
+	t.Run("form_repeated_tags", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodPost, "/articles", strings.NewReader(fields.Encode()))
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		article, err := decodeArticle(request, true)
+		assert.NoError(t, err)
+		assert.Equal(t, want, article)
+	})
+
+	t.Run("multipart_repeated_tags", func(t *testing.T) {
+		article, err := decodeArticle(multipartRequest(t, fields, ""), true)
+		assert.NoError(t, err)
+		assert.Equal(t, want, article)
+	})
+
+	t.Run("multipart_body_file_part", func(t *testing.T) {
+		withoutBody := url.Values{formFieldTitle: {"test"}, formFieldDate: {"2023-03-30"}, formFieldTags: {"fun", "health"}}
+		article, err := decodeArticle(multipartRequest(t, withoutBody, "test body"), true)
+		assert.NoError(t, err)
+		assert.Equal(t, want, article)
+	})
+
+	t.Run("multipart_unknown_field", func(t *testing.T) {
+		unknown := url.Values{formFieldTitle: {"test"}, "author": {"someone"}}
+		_, err := decodeArticle(multipartRequest(t, unknown, ""), true)
+		assertErrorCode(t, err, UnknownFieldError, http.StatusBadRequest)
+
+		// unknown fields are ignored when decoding is not strict
+		_, err = decodeArticle(multipartRequest(t, unknown, ""), false)
+		assert.NoError(t, err)
+	})
+
+	for _, contentType := range []string{"text/plain", "application/xml", "multipart/mixed; boundary=x", "not a type"} {
+		t.Run("unsupported_"+contentType, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodPost, "/articles", strings.NewReader("title=test"))
+			request.Header.Set("Content-Type", contentType)
+			_, err := decodeArticle(request, false)
+			assertErrorCode(t, err, UnsupportedMediaTypeError, http.StatusUnsupportedMediaType)
+		})
+	}
+}
+
+// assertErrorCode - assert the internal error code and the http status the error is mapped to
+func assertErrorCode(t *testing.T, err error, code, status int) {
+	t.Helper()
+	if !assert.Error(t, err) {
+		return
+	}
+	fields := mapError(err)
+	assert.Equal(t, code, fields.code, err.Error())
+	assert.Equal(t, status, fields.httpStatusCode, err.Error())
+}
+
+func TestDecodeJSONBody(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		contentType string
+		strict      bool
+		limit       int64
+		wantCode    int
+		wantStatus  int
+		wantErr     bool
+	}{
+		{name: "valid", body: `{"title":"test"}`, contentType: "application/json", strict: true},
+		{name: "suffix_json", body: `{"title":"test"}`, contentType: "application/merge-patch+json", strict: true},
+		{name: "unknown_field", body: `{"title":"test","author":"someone"}`, contentType: "application/json", strict: true,
+			wantErr: true, wantCode: UnknownFieldError, wantStatus: http.StatusBadRequest},
+		{name: "unknown_field_not_strict", body: `{"title":"test","author":"someone"}`},
+		{name: "trailing_data", body: `{"title":"test"} {"title":"other"}`, contentType: "application/json", strict: true,
+			wantErr: true, wantCode: TrailingDataError, wantStatus: http.StatusBadRequest},
+		{name: "trailing_garbage", body: `{"title":"test"}]`, contentType: "application/json", strict: true,
+			wantErr: true, wantCode: TrailingDataError, wantStatus: http.StatusBadRequest},
+		{name: "trailing_data_not_strict", body: `{"title":"test"} {"title":"other"}`},
+		{name: "trailing_whitespace", body: "{\"title\":\"test\"}\n", contentType: "application/json", strict: true},
+		{name: "missing_content_type", body: `{"title":"test"}`, strict: true,
+			wantErr: true, wantCode: MissingContentTypeError, wantStatus: http.StatusUnsupportedMediaType},
+		{name: "missing_content_type_not_strict", body: `{"title":"test"}`},
+		{name: "not_json", body: `{"title":"test"}`, contentType: "text/plain", strict: true,
+			wantErr: true, wantCode: UnsupportedMediaTypeError, wantStatus: http.StatusUnsupportedMediaType},
+		{name: "syntax_error", body: `{"title":`, contentType: "application/json", strict: true,
+			wantErr: true, wantCode: InvalidPayloadError, wantStatus: http.StatusBadRequest},
+		{name: "too_large", body: `{"title":"a long title"}`, contentType: "application/json", strict: true, limit: 10,
+			wantErr: true, wantCode: PayloadTooLargeError, wantStatus: http.StatusRequestEntityTooLarge},
+		// the trailing value is over the limit, reported as too large rather than as trailing data
+		{name: "trailing_too_large", body: `{"title":"t"} {"title":"other"}`, contentType: "application/json", strict: true,
+			limit: 20, wantErr: true, wantCode: PayloadTooLargeError, wantStatus: http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodPost, "/articles", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				request.Header.Set("Content-Type", tt.contentType)
+			}
+			if tt.limit > 0 {
+				request.Body = &limitedBody{ReadCloser: request.Body, limit: tt.limit, remaining: tt.limit}
+			}
+			var article models.Article
+			err := decodeJSONBody(request, &article, tt.strict)
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				assert.Equal(t, "test", article.Title)
+				return
+			}
+			assertErrorCode(t, err, tt.wantCode, tt.wantStatus)
+		})
+	}
+}
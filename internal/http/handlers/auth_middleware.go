@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"article-dispatcher/internal/domain/adaptors/logger"
+	"article-dispatcher/internal/http/auth"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// AuthMiddleware authenticates the request with the first authenticator its credentials belong to and authorizes
+// the scope required by the matched route, routes without a required scope are only allowed for the admin scope
+// and public routes are not authenticated
+type AuthMiddleware struct {
+	Log            logger.Logger
+	Authenticators []auth.Authenticator
+	RouteScopes    map[string]auth.Scope
+	ErrorHandler   ErrorHandler
+}
+
+func (am AuthMiddleware) MiddleFunc(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		route := ""
+		if r := mux.CurrentRoute(request); r != nil {
+			route = r.GetName()
+		}
+		scope, ok := am.RouteScopes[route]
+		if !ok {
+			scope = auth.ScopeAdmin
+		}
+		if scope == auth.ScopePublic {
+			handler.ServeHTTP(writer, request)
+			return
+		}
+
+		identity, err := am.authenticate(request)
+		if err != nil {
+			for _, a := range am.Authenticators {
+				writer.Header().Add("WWW-Authenticate", a.Challenge())
+			}
+			am.ErrorHandler.Handle(request.Context(), writer, authError(err))
+			return
+		}
+		if !identity.HasScope(scope) {
+			am.ErrorHandler.Handle(request.Context(), writer,
+				Forbidden{fmt.Errorf("identity [%s] is missing the [%s] scope", identity.ID, scope)})
+			return
+		}
+
+		ctx := context.WithValue(request.Context(), ParamIdentity, identity)
+		requestLog := logger.FromContext(ctx, am.Log).With(logger.F("client", identity.ID))
+		ctx = logger.NewContext(ctx, requestLog)
+		if identity.Claims != nil {
+			ctx = context.WithValue(ctx, ParamClaims, *identity.Claims)
+			requestLog.Info("request authenticated", logger.F("issuer", identity.Claims.Issuer))
+		} else {
+			requestLog.Info("request authenticated")
+		}
+		handler.ServeHTTP(writer, request.WithContext(ctx))
+	})
+}
+
+// authenticate authenticate with the first authenticator finding its credentials in the request
+func (am AuthMiddleware) authenticate(request *http.Request) (auth.Identity, error) {
+	for _, a := range am.Authenticators {
+		identity, err := a.Authenticate(request)
+		if errors.Is(err, auth.ErrMissingCredentials) {
+			continue
+		}
+		return identity, err
+	}
+	return auth.Identity{}, auth.ErrMissingCredentials
+}
+
+// authError map the authentication errors into the unauthenticated handler errors
+func authError(err error) error {
+	switch {
+	case errors.Is(err, auth.ErrTokenExpired), errors.Is(err, auth.ErrTokenNotYetValid):
+		return ExpiredToken{err}
+	case errors.Is(err, auth.ErrTokenIssuer), errors.Is(err, auth.ErrTokenAudience):
+		return InvalidTokenClaims{err}
+	case errors.Is(err, auth.ErrTokenMalformed), errors.Is(err, auth.ErrTokenSignature),
+		errors.Is(err, auth.ErrTokenAlgorithm):
+		return InvalidToken{err}
+	default:
+		return Unauthenticated{err}
+	}
+}
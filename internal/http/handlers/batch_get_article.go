@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"article-dispatcher/internal/domain/adaptors/logger"
+	"article-dispatcher/internal/domain/services"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"fmt"
+	"net/http"
+	"time"
+)
+
+type ArticleBatchGetHandler struct {
+	Log                  logger.Logger
+	ArticleService       services.ArticleService
+	ErrorHandler         ErrorHandler
+	RequestLatencyReport *prometheus.SummaryVec
+	// MaxIDs maximum number of ids accepted in a single batch, zero means unlimited
+	MaxIDs int
+	// StrictDecoding rejects unknown fields, trailing data and bodies which are not json
+	StrictDecoding bool
+}
+
+// batchGetRequest - request body of the POST variant of the batch get
+type batchGetRequest struct {
+	IDs []string `json:"ids"`
+}
+
+// ServeHTTP return the articles found for the requested ids along with the missing ids,
+// ids are read from the `ids` query parameter on GET and from the request body on POST
+func (bg ArticleBatchGetHandler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	start := time.Now()
+	var err error
+	defer func() {
+		bg.RequestLatencyReport.
+			With(map[string]string{"endpoint": "batch_get_article", "error": fmt.Sprintf(`%t`, err != nil)}).
+			Observe(float64(time.Since(start).Microseconds()))
+	}()
+
+	var ids []string
+	if request.Method == http.MethodPost {
+		var batchRequest batchGetRequest
+		err = decodeJSONBody(request, &batchRequest, bg.StrictDecoding)
+		if err != nil {
+			bg.ErrorHandler.Handle(request.Context(), writer, err)
+			return
+		}
+		ids = batchRequest.IDs
+	} else {
+		ids = splitQueryList(request.URL.Query()[QueryParameterIDs])
+	}
+
+	// validate input article ids
+	ids, err = bg.validateIDs(ids)
+	if err != nil {
+		bg.ErrorHandler.Handle(request.Context(), writer, ValidationError{err})
+		return
+	}
+
+	articles, err := bg.ArticleService.GetMany(request.Context(), ids)
+	if err != nil {
+		err = fmt.Errorf("error fetching articles data due to, %w", err)
+		bg.ErrorHandler.Handle(request.Context(), writer, err)
+		return
+	}
+
+	r, contentType, err := encodeResponse(request.Context(), articles)
+	if err != nil {
+		bg.ErrorHandler.Handle(request.Context(), writer, err)
+		return
+	}
+	writer.Header().Add("Content-Type", contentType)
+	writer.WriteHeader(http.StatusOK)
+	_, err = writer.Write(r)
+	if err != nil {
+		logger.FromContext(request.Context(), bg.Log).Error("error writing to response", logger.F("error", err))
+	}
+}
+
+// validateIDs - validate each of the requested ids and the batch size, duplicated ids are removed
+// keeping the requested order
+func (bg ArticleBatchGetHandler) validateIDs(ids []string) ([]string, error) {
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("at least one article id is required")
+	}
+
+	seen := make(map[string]struct{}, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if !validateArticleID(id) {
+			return nil, fmt.Errorf("invalid article id format [%s]", id)
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		unique = append(unique, id)
+	}
+
+	if bg.MaxIDs > 0 && len(unique) > bg.MaxIDs {
+		return nil, fmt.Errorf("too many article ids [%d], maximum allowed is [%d]", len(unique), bg.MaxIDs)
+	}
+	return unique, nil
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	"net/url"
+	"testing"
+)
+
+func TestArticleBatchGetHandler_validateIDs(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		maxIDs  int
+		want    []string
+		wantErr bool
+	}{
+		{name: "single_id", query: "ids=1", maxIDs: 3, want: []string{"1"}},
+		{name: "comma_separated_and_repeated", query: "ids=1,2&ids=3", maxIDs: 3, want: []string{"1", "2", "3"}},
+		{name: "duplicates_removed_in_order", query: "ids=2,1,2,1", maxIDs: 3, want: []string{"2", "1"}},
+		{name: "duplicates_not_counted_in_the_limit", query: "ids=1,2,3,3,3", maxIDs: 3, want: []string{"1", "2", "3"}},
+		{name: "unlimited", query: "ids=1,2,3,4", maxIDs: 0, want: []string{"1", "2", "3", "4"}},
+		{name: "empty_ids", query: "ids=", maxIDs: 3, wantErr: true},
+		{name: "only_separators", query: "ids=,,", maxIDs: 3, wantErr: true},
+		{name: "over_the_limit", query: "ids=1,2,3,4", maxIDs: 3, wantErr: true},
+		{name: "invalid_id", query: "ids=1,-2", maxIDs: 3, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			assert.NoError(t, err)
+			bg := ArticleBatchGetHandler{MaxIDs: tt.maxIDs}
+			got, err := bg.validateIDs(splitQueryList(query[QueryParameterIDs]))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
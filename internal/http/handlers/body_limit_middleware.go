@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"github.com/pkg/errors"
+
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// errPayloadTooLarge request body exceeded the maximum body size
+var errPayloadTooLarge = errors.New("request body too large")
+
+// BodyLimitMiddleware limits the request bodies to `MaxBytes`, registered after the compression middleware it limits
+// the decompressed bodies
+type BodyLimitMiddleware struct {
+	MaxBytes     int64
+	ErrorHandler ErrorHandler
+}
+
+func (bm BodyLimitMiddleware) MiddleFunc(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.ContentLength > bm.MaxBytes {
+			bm.ErrorHandler.Handle(request.Context(), writer, PayloadTooLarge{
+				fmt.Errorf("request body of %d bytes exceeds the limit of %d bytes", request.ContentLength, bm.MaxBytes)})
+			return
+		}
+		request.Body = &limitedBody{ReadCloser: request.Body, limit: bm.MaxBytes, remaining: bm.MaxBytes}
+		handler.ServeHTTP(writer, request)
+	})
+}
+
+// limitedBody request body failing with errPayloadTooLarge once more than the limit is read
+type limitedBody struct {
+	io.ReadCloser
+	limit     int64
+	remaining int64
+	exceeded  bool
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.exceeded {
+		return 0, errPayloadTooLarge
+	}
+	// read one byte over the remaining bytes to detect the bodies over the limit
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+	n, err := b.ReadCloser.Read(p)
+	if int64(n) <= b.remaining {
+		b.remaining -= int64(n)
+		return n, err
+	}
+	n = int(b.remaining)
+	b.remaining = 0
+	b.exceeded = true
+	return n, errPayloadTooLarge
+}
+
+// bodyError map the error reading the request body, bodies over the limit are reported as too large even when the
+// reading error does not wrap errPayloadTooLarge
+func bodyError(request *http.Request, err error, message string) error {
+	body, ok := request.Body.(*limitedBody)
+	if errors.Is(err, errPayloadTooLarge) || (ok && body.exceeded) {
+		limit := int64(0)
+		if ok {
+			limit = body.limit
+		}
+		return PayloadTooLarge{fmt.Errorf("%s, request body exceeds the limit of %d bytes", message, limit)}
+	}
+	return InvalidPayload{fmt.Errorf("%s due to, %w", message, err)}
+}
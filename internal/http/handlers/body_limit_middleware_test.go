@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// decodingHandler handler decoding the json request body strictly, answering the decoding errors
+func decodingHandler(errorHandler ErrorHandler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		var v map[string]interface{}
+		if err := decodeJSONBody(request, &v, true); err != nil {
+			errorHandler.Handle(request.Context(), writer, err)
+			return
+		}
+		writer.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func TestBodyLimitMiddleware(t *testing.T) {
+	bm := BodyLimitMiddleware{MaxBytes: 16, ErrorHandler: testErrorHandler(t)}
+	calls := 0
+	decoding := decodingHandler(bm.ErrorHandler)
+	handler := bm.MiddleFunc(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		calls++
+		decoding.ServeHTTP(writer, request)
+	}))
+
+	tests := []struct {
+		name          string
+		body          string
+		contentLength int64
+		wantStatus    int
+		wantCalled    bool
+	}{
+		{name: "under_limit", body: `{"a":"b"}`, contentLength: 9, wantStatus: http.StatusNoContent, wantCalled: true},
+		{name: "at_limit", body: `{"a":"12345678"}`, contentLength: -1, wantStatus: http.StatusNoContent, wantCalled: true},
+		{name: "content_length_early_reject", body: `{"a":"b"}`, contentLength: 17,
+			wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "chunked_over_limit", body: `{"a":"123456789"}`, contentLength: -1,
+			wantStatus: http.StatusRequestEntityTooLarge, wantCalled: true},
+		{name: "understated_content_length", body: `{"a":"1234567890123"}`, contentLength: 9,
+			wantStatus: http.StatusRequestEntityTooLarge, wantCalled: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = 0
+			request := httptest.NewRequest(http.MethodPost, "/articles", strings.NewReader(tt.body))
+			request.Header.Set("Content-Type", "application/json")
+			request.ContentLength = tt.contentLength
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			assert.Equal(t, tt.wantStatus, recorder.Code, recorder.Body.String())
+			assert.Equal(t, tt.wantCalled, calls == 1)
+		})
+	}
+}
+
+func TestLimitedBody(t *testing.T) {
+	t.Run("at_limit", func(t *testing.T) {
+		body := &limitedBody{ReadCloser: io.NopCloser(strings.NewReader("12345")), limit: 5, remaining: 5}
+		data, err := io.ReadAll(body)
+		assert.NoError(t, err)
+		assert.Equal(t, "12345", string(data))
+		assert.False(t, body.exceeded)
+	})
+
+	t.Run("over_limit", func(t *testing.T) {
+		body := &limitedBody{ReadCloser: io.NopCloser(strings.NewReader("123456")), limit: 5, remaining: 5}
+		data, err := io.ReadAll(body)
+		assert.True(t, errors.Is(err, errPayloadTooLarge))
+		assert.Equal(t, "12345", string(data))
+		assert.True(t, body.exceeded)
+
+		// the following reads keep failing
+		n, err := body.Read(make([]byte, 8))
+		assert.Equal(t, 0, n)
+		assert.True(t, errors.Is(err, errPayloadTooLarge))
+	})
+}
+
+func TestBodyError(t *testing.T) {
+	readErr := errors.New("unexpected EOF")
+	tests := []struct {
+		name       string
+		body       io.ReadCloser
+		err        error
+		wantCode   int
+		wantStatus int
+	}{
+		{name: "too_large", body: http.NoBody, err: errPayloadTooLarge,
+			wantCode: PayloadTooLargeError, wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "wrapped_too_large", body: http.NoBody, err: errors.Wrap(errPayloadTooLarge, "decoding"),
+			wantCode: PayloadTooLargeError, wantStatus: http.StatusRequestEntityTooLarge},
+		// the decoder may report a syntax error for the truncated body instead of the limit error
+		{name: "exceeded_body", body: &limitedBody{ReadCloser: http.NoBody, limit: 5, exceeded: true}, err: readErr,
+			wantCode: PayloadTooLargeError, wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "read_error", body: &limitedBody{ReadCloser: http.NoBody, limit: 5, remaining: 5}, err: readErr,
+			wantCode: InvalidPayloadError, wantStatus: http.StatusBadRequest},
+		{name: "unlimited_read_error", body: http.NoBody, err: readErr,
+			wantCode: InvalidPayloadError, wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodPost, "/articles", nil)
+			request.Body = tt.body
+			assertErrorCode(t, bodyError(request, tt.err, "error decoding request body"), tt.wantCode, tt.wantStatus)
+		})
+	}
+}
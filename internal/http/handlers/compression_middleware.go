@@ -0,0 +1,304 @@
+package handlers
+
+import (
+	"article-dispatcher/internal/domain/adaptors/logger"
+
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	encodingGzip     = "gzip"
+	encodingDeflate  = "deflate"
+	encodingIdentity = "identity"
+)
+
+// CompressionMiddleware negotiates the response compression with the `Accept-Encoding` header and
+// decompresses the gzip and deflate encoded request bodies
+type CompressionMiddleware struct {
+	ErrorHandler ErrorHandler
+	// MinSize responses smaller than this number of bytes are not compressed
+	MinSize int
+	// ContentTypes media types allowed to be compressed
+	ContentTypes []string
+	gzipPool     *sync.Pool
+	deflatePool  *sync.Pool
+}
+
+// NewCompressionMiddleware create the compression middleware with pooled writers of the compression level
+func NewCompressionMiddleware(errorHandler ErrorHandler, level, minSize int, contentTypes []string) *CompressionMiddleware {
+	return &CompressionMiddleware{
+		ErrorHandler: errorHandler,
+		MinSize:      minSize,
+		ContentTypes: contentTypes,
+		gzipPool: &sync.Pool{New: func() interface{} {
+			// level is validated with the router configurations
+			w, _ := gzip.NewWriterLevel(io.Discard, level)
+			return w
+		}},
+		deflatePool: &sync.Pool{New: func() interface{} {
+			w, _ := zlib.NewWriterLevel(io.Discard, level)
+			return w
+		}},
+	}
+}
+
+func (cm *CompressionMiddleware) MiddleFunc(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if err := decompressRequest(request); err != nil {
+			cm.ErrorHandler.Handle(request.Context(), writer, err)
+			return
+		}
+
+		// conditional requests carry the etags of the compressed responses
+		ifNoneMatch := request.Header.Get(HeaderIfNoneMatch)
+		for _, header := range []string{HeaderIfNoneMatch, HeaderIfMatch} {
+			if value := request.Header.Get(header); value != "" {
+				request.Header.Set(header, stripETagEncoding(value))
+			}
+		}
+
+		cw := &compressWriter{
+			ResponseWriter: writer,
+			middleware:     cm,
+			encoding:       negotiateEncoding(request.Header.Get("Accept-Encoding")),
+			head:           request.Method == http.MethodHead,
+			ifNoneMatch:    ifNoneMatch,
+		}
+		handler.ServeHTTP(cw, request)
+		if err := cw.close(); err != nil {
+			logger.FromContext(request.Context(), cm.ErrorHandler.Log).Error("error writing compressed response", logger.F("error", err))
+		}
+	})
+}
+
+// decompressRequest - replace the gzip or deflate encoded request body with the decompressed body
+func decompressRequest(request *http.Request) error {
+	encoding := strings.ToLower(strings.TrimSpace(request.Header.Get("Content-Encoding")))
+	var body io.ReadCloser
+	var err error
+	switch encoding {
+	case "", encodingIdentity:
+		return nil
+	case encodingGzip:
+		body, err = gzip.NewReader(request.Body)
+	case encodingDeflate:
+		body, err = zlib.NewReader(request.Body)
+	default:
+		return UnsupportedContentEncoding{fmt.Errorf("unsupported request content encoding [%s]", encoding)}
+	}
+	if err != nil {
+		return InvalidPayload{fmt.Errorf("error decompressing request body due to, %w", err)}
+	}
+
+	request.Body = body
+	request.ContentLength = -1
+	request.Header.Del("Content-Length")
+	request.Header.Del("Content-Encoding")
+	return nil
+}
+
+// negotiateEncoding - pick the supported encoding with the highest quality value, gzip is preferred on ties and
+// identity is used when nothing else is acceptable
+func negotiateEncoding(acceptEncoding string) string {
+	type candidate struct {
+		encoding string
+		quality  float64
+	}
+	qualities := make(map[string]float64)
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		fields := strings.Split(part, ";")
+		encoding := strings.ToLower(strings.TrimSpace(fields[0]))
+		if encoding == "" {
+			continue
+		}
+		quality := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if q, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64); err == nil {
+					quality = q
+				}
+			}
+		}
+		qualities[encoding] = quality
+	}
+
+	candidates := make([]candidate, 0)
+	for _, encoding := range []string{encodingGzip, encodingDeflate} {
+		quality, ok := qualities[encoding]
+		if !ok {
+			quality, ok = qualities["*"]
+		}
+		if ok && quality > 0 {
+			candidates = append(candidates, candidate{encoding: encoding, quality: quality})
+		}
+	}
+	if len(candidates) == 0 {
+		return encodingIdentity
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].quality > candidates[j].quality
+	})
+	return candidates[0].encoding
+}
+
+// stripETagEncoding - remove the encoding suffixes added to the etags of the compressed responses
+func stripETagEncoding(header string) string {
+	for _, encoding := range []string{encodingGzip, encodingDeflate} {
+		header = strings.ReplaceAll(header, fmt.Sprintf(`-%s"`, encoding), `"`)
+	}
+	return header
+}
+
+// compressWriter - buffers the response until the minimum size is reached to decide whether to compress it
+type compressWriter struct {
+	http.ResponseWriter
+	middleware *CompressionMiddleware
+	encoding   string
+	head       bool
+	// ifNoneMatch etags of the If-None-Match header before the encoding suffixes were stripped
+	ifNoneMatch string
+	status      int
+	buf         []byte
+	decided     bool
+	compressor  io.WriteCloser
+	pool        *sync.Pool
+}
+
+func (cw *compressWriter) WriteHeader(status int) {
+	if cw.status == 0 {
+		cw.status = status
+	}
+}
+
+func (cw *compressWriter) Write(b []byte) (int, error) {
+	if cw.status == 0 {
+		cw.status = http.StatusOK
+	}
+	if cw.decided {
+		if cw.compressor != nil {
+			return cw.compressor.Write(b)
+		}
+		return cw.ResponseWriter.Write(b)
+	}
+
+	cw.buf = append(cw.buf, b...)
+	if len(cw.buf) >= cw.middleware.MinSize {
+		if err := cw.decide(); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+// decide - write the headers compressing the response if it is allowed, and flush the buffered body
+func (cw *compressWriter) decide() error {
+	cw.decided = true
+	header := cw.Header()
+	allowed := cw.allowedContentType(header.Get("Content-Type"))
+	if allowed {
+		header.Add("Vary", "Accept-Encoding")
+	}
+
+	if allowed && cw.encoding != encodingIdentity && !cw.head && len(cw.buf) >= cw.middleware.MinSize &&
+		cw.status != http.StatusNoContent && cw.status != http.StatusNotModified &&
+		header.Get("Content-Encoding") == "" {
+		cw.startCompression(header)
+	}
+
+	if cw.status == http.StatusNotModified {
+		cw.notModifiedETag(header)
+	}
+
+	cw.ResponseWriter.WriteHeader(cw.status)
+	if len(cw.buf) == 0 {
+		return nil
+	}
+	var err error
+	if cw.compressor != nil {
+		_, err = cw.compressor.Write(cw.buf)
+	} else {
+		_, err = cw.ResponseWriter.Write(cw.buf)
+	}
+	cw.buf = nil
+	return err
+}
+
+// startCompression - take a pooled compressor of the negotiated encoding and set the response headers
+func (cw *compressWriter) startCompression(header http.Header) {
+	switch cw.encoding {
+	case encodingGzip:
+		cw.pool = cw.middleware.gzipPool
+		w := cw.pool.Get().(*gzip.Writer)
+		w.Reset(cw.ResponseWriter)
+		cw.compressor = w
+	case encodingDeflate:
+		cw.pool = cw.middleware.deflatePool
+		w := cw.pool.Get().(*zlib.Writer)
+		w.Reset(cw.ResponseWriter)
+		cw.compressor = w
+	}
+
+	header.Set("Content-Encoding", cw.encoding)
+	header.Del("Content-Length")
+	// strong etags must differ between the representations
+	if etag := header.Get(HeaderETag); strings.HasPrefix(etag, `"`) {
+		header.Set(HeaderETag, fmt.Sprintf(`%s-%s"`, strings.TrimSuffix(etag, `"`), cw.encoding))
+	}
+}
+
+// notModifiedETag - keep the encoding suffix on the etag of a 304 revalidating a compressed response, so that the
+// validator does not change between the two responses
+func (cw *compressWriter) notModifiedETag(header http.Header) {
+	etag := header.Get(HeaderETag)
+	if cw.encoding == encodingIdentity || !strings.HasPrefix(etag, `"`) || strings.TrimSpace(cw.ifNoneMatch) == "*" {
+		return
+	}
+	encoded := fmt.Sprintf(`%s-%s"`, strings.TrimSuffix(etag, `"`), cw.encoding)
+	if matchETag(cw.ifNoneMatch, encoded) {
+		header.Set(HeaderETag, encoded)
+		header.Add("Vary", "Accept-Encoding")
+	}
+}
+
+// allowedContentType - check the media type of the response against the allowed content types
+func (cw *compressWriter) allowedContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, allowed := range cw.middleware.ContentTypes {
+		if strings.EqualFold(mediaType, strings.TrimSpace(allowed)) {
+			return true
+		}
+	}
+	return false
+}
+
+// close - flush the responses smaller than the minimum size and return the compressor into the pool
+func (cw *compressWriter) close() error {
+	if !cw.decided {
+		if cw.status == 0 {
+			return nil
+		}
+		if err := cw.decide(); err != nil {
+			return err
+		}
+	}
+	if cw.compressor == nil {
+		return nil
+	}
+	err := cw.compressor.Close()
+	cw.pool.Put(cw.compressor)
+	cw.compressor = nil
+	return err
+}
@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"article-dispatcher/internal/pkg/log"
+
+	"github.com/stretchr/testify/assert"
+
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testErrorHandler error handler logging only the fatal entries
+func testErrorHandler(t *testing.T) ErrorHandler {
+	l, err := log.NewLogger(log.FATAL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ErrorHandler{Log: l, ProblemTypeBase: "/errors/"}
+}
+
+// bodyHandler handler writing the body with the content type and the etag, answering 304 when it matches
+func bodyHandler(contentType, body string) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if writeConditional(writer, request, `"v3"`, time.Time{}, "") {
+			return
+		}
+		writer.Header().Set("Content-Type", contentType)
+		_, _ = writer.Write([]byte(body))
+	})
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		acceptEncoding string
+		want           string
+	}{
+		{acceptEncoding: "", want: encodingIdentity},
+		{acceptEncoding: "gzip", want: encodingGzip},
+		{acceptEncoding: "deflate", want: encodingDeflate},
+		{acceptEncoding: "deflate, gzip", want: encodingGzip},
+		{acceptEncoding: "gzip;q=0.5, deflate;q=0.8", want: encodingDeflate},
+		{acceptEncoding: "GZIP ; q=1", want: encodingGzip},
+		{acceptEncoding: "gzip;q=0", want: encodingIdentity},
+		{acceptEncoding: "*", want: encodingGzip},
+		{acceptEncoding: "*;q=0.5, gzip;q=0", want: encodingDeflate},
+		{acceptEncoding: "br, identity", want: encodingIdentity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.acceptEncoding, func(t *testing.T) {
+			assert.Equal(t, tt.want, negotiateEncoding(tt.acceptEncoding))
+		})
+	}
+}
+
+func TestStripETagEncoding(t *testing.T) {
+	assert.Equal(t, `"v3"`, stripETagEncoding(`"v3-gzip"`))
+	assert.Equal(t, `"v3", W/"v4"`, stripETagEncoding(`"v3-deflate", W/"v4-gzip"`))
+	assert.Equal(t, `"v3-br"`, stripETagEncoding(`"v3-br"`))
+}
+
+func TestCompressionMiddleware_Compression(t *testing.T) {
+	large := strings.Repeat("article ", 32)
+	tests := []struct {
+		name           string
+		method         string
+		acceptEncoding string
+		contentType    string
+		body           string
+		wantEncoding   string
+		wantVary       bool
+	}{
+		{name: "gzip", acceptEncoding: "gzip", contentType: "application/json", body: large, wantEncoding: "gzip", wantVary: true},
+		{name: "deflate", acceptEncoding: "deflate", contentType: "application/json", body: large, wantEncoding: "deflate", wantVary: true},
+		{name: "below_min_size", acceptEncoding: "gzip", contentType: "application/json", body: "{}", wantVary: true},
+		{name: "not_accepted", contentType: "application/json", body: large, wantVary: true},
+		{name: "content_type_not_allowed", acceptEncoding: "gzip", contentType: "image/png", body: large},
+		{name: "content_type_parameters", acceptEncoding: "gzip", contentType: "text/plain; charset=utf-8", body: large,
+			wantEncoding: "gzip", wantVary: true},
+		{name: "head", method: http.MethodHead, acceptEncoding: "gzip", contentType: "application/json", body: large,
+			wantVary: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cm := NewCompressionMiddleware(testErrorHandler(t), gzip.DefaultCompression, 64,
+				[]string{"application/json", "text/plain"})
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			request := httptest.NewRequest(method, "/articles/1", nil)
+			request.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			writer := httptest.NewRecorder()
+			cm.MiddleFunc(bodyHandler(tt.contentType, tt.body)).ServeHTTP(writer, request)
+
+			assert.Equal(t, http.StatusOK, writer.Code)
+			assert.Equal(t, tt.wantEncoding, writer.Header().Get("Content-Encoding"))
+			assert.Equal(t, tt.wantVary, writer.Header().Get("Vary") == "Accept-Encoding")
+			body := writer.Body.Bytes()
+			switch tt.wantEncoding {
+			case encodingGzip:
+				body = inflate(t, encodingGzip, body)
+				assert.Equal(t, `"v3-gzip"`, writer.Header().Get(HeaderETag))
+			case encodingDeflate:
+				body = inflate(t, encodingDeflate, body)
+				assert.Equal(t, `"v3-deflate"`, writer.Header().Get(HeaderETag))
+			default:
+				assert.Equal(t, `"v3"`, writer.Header().Get(HeaderETag))
+			}
+			if method == http.MethodGet {
+				assert.Equal(t, tt.body, string(body))
+			}
+		})
+	}
+}
+
+func TestCompressionMiddleware_ETagRoundTrip(t *testing.T) {
+	cm := NewCompressionMiddleware(testErrorHandler(t), gzip.DefaultCompression, 64, []string{"application/json"})
+	handler := cm.MiddleFunc(bodyHandler("application/json", strings.Repeat("article ", 32)))
+
+	request := httptest.NewRequest(http.MethodGet, "/articles/1", nil)
+	request.Header.Set("Accept-Encoding", "gzip")
+	writer := httptest.NewRecorder()
+	handler.ServeHTTP(writer, request)
+	etag := writer.Header().Get(HeaderETag)
+	assert.Equal(t, `"v3-gzip"`, etag)
+
+	// the 304 carries the same validator as the compressed response
+	request = httptest.NewRequest(http.MethodGet, "/articles/1", nil)
+	request.Header.Set("Accept-Encoding", "gzip")
+	request.Header.Set(HeaderIfNoneMatch, etag)
+	writer = httptest.NewRecorder()
+	handler.ServeHTTP(writer, request)
+	assert.Equal(t, http.StatusNotModified, writer.Code)
+	assert.Equal(t, etag, writer.Header().Get(HeaderETag))
+	assert.Equal(t, "Accept-Encoding", writer.Header().Get("Vary"))
+	assert.Empty(t, writer.Body.Bytes())
+
+	// revalidating the uncompressed response keeps the uncompressed etag
+	request = httptest.NewRequest(http.MethodGet, "/articles/1", nil)
+	request.Header.Set("Accept-Encoding", "gzip")
+	request.Header.Set(HeaderIfNoneMatch, `"v3"`)
+	writer = httptest.NewRecorder()
+	handler.ServeHTTP(writer, request)
+	assert.Equal(t, http.StatusNotModified, writer.Code)
+	assert.Equal(t, `"v3"`, writer.Header().Get(HeaderETag))
+}
+
+func TestCompressionMiddleware_RequestBody(t *testing.T) {
+	echo := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		body, err := io.ReadAll(request.Body)
+		if err != nil {
+			writer.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		assert.Empty(t, request.Header.Get("Content-Encoding"))
+		writer.Header().Set("Content-Type", "text/plain")
+		_, _ = writer.Write(body)
+	})
+	cm := NewCompressionMiddleware(testErrorHandler(t), gzip.DefaultCompression, 1024, []string{"text/plain"})
+
+	var compressed bytes.Buffer
+	gw := gzip.NewWriter(&compressed)
+	_, _ = gw.Write([]byte(`{"title": "test"}`))
+	assert.NoError(t, gw.Close())
+	request := httptest.NewRequest(http.MethodPost, "/articles", &compressed)
+	request.Header.Set("Content-Encoding", "gzip")
+	writer := httptest.NewRecorder()
+	cm.MiddleFunc(echo).ServeHTTP(writer, request)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.Equal(t, `{"title": "test"}`, writer.Body.String())
+
+	// bodies which are not gzip encoded are rejected
+	request = httptest.NewRequest(http.MethodPost, "/articles", strings.NewReader(`{"title": "test"}`))
+	request.Header.Set("Content-Encoding", "gzip")
+	writer = httptest.NewRecorder()
+	cm.MiddleFunc(echo).ServeHTTP(writer, request)
+	assert.Equal(t, http.StatusBadRequest, writer.Code)
+
+	// unsupported encodings are rejected
+	request = httptest.NewRequest(http.MethodPost, "/articles", strings.NewReader(`{"title": "test"}`))
+	request.Header.Set("Content-Encoding", "br")
+	writer = httptest.NewRecorder()
+	cm.MiddleFunc(echo).ServeHTTP(writer, request)
+	assert.Equal(t, http.StatusUnsupportedMediaType, writer.Code)
+}
+
+// inflate - decompress the body of the encoding
+func inflate(t *testing.T, encoding string, body []byte) []byte {
+	var reader io.ReadCloser
+	var err error
+	if encoding == encodingGzip {
+		reader, err = gzip.NewReader(bytes.NewReader(body))
+	} else {
+		reader, err = zlib.NewReader(bytes.NewReader(body))
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+	inflated, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return inflated
+}
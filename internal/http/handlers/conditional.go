@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// writeConditional - set the ETag, Last-Modified and Cache-Control headers of the response and
+// respond 304 Not Modified when the request preconditions match, returns true if the response was written
+func writeConditional(writer http.ResponseWriter, request *http.Request, etag string, lastModified time.Time,
+	cacheControl string) bool {
+	writer.Header().Set(HeaderETag, etag)
+	if !lastModified.IsZero() {
+		writer.Header().Set(HeaderLastModified, lastModified.UTC().Format(http.TimeFormat))
+	}
+	if cacheControl != "" {
+		writer.Header().Set(HeaderCacheControl, cacheControl)
+	}
+
+	if !notModified(request, etag, lastModified) {
+		return false
+	}
+	writer.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// notModified - evaluate the If-None-Match and If-Modified-Since preconditions, If-Modified-Since is
+// ignored when If-None-Match is present
+func notModified(request *http.Request, etag string, lastModified time.Time) bool {
+	if inm := request.Header.Get(HeaderIfNoneMatch); inm != "" {
+		return matchETag(inm, etag)
+	}
+
+	ims := request.Header.Get(HeaderIfModifiedSince)
+	if ims == "" || lastModified.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(ims)
+	if err != nil {
+		return false
+	}
+	// http dates only have the precision of seconds
+	return !lastModified.Truncate(time.Second).After(since)
+}
+
+// matchETag - match the etag against the etag list of a precondition header using the weak comparison,
+// which ignores the weak `W/` prefixes
+func matchETag(header, etag string) bool {
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// strongETag - strong etag of the response body, which differs between the representations of the negotiated
+// media types
+func strongETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return fmt.Sprintf(`"%s"`, hex.EncodeToString(sum[:16]))
+}
+
+// versionETag - strong etag of the article version in the representation of the content type, e.g. `"v2-json"`,
+// so that the representations of the negotiated media types do not share a validator
+func versionETag(version int64, contentType string) string {
+	return fmt.Sprintf(`"v%d-%s"`, version, representation(contentType))
+}
+
+// representation - short name of the media type of the content type, e.g. `json` for `application/json`
+func representation(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = contentType
+	}
+	parts := strings.SplitN(mediaType, "/", 2)
+	return strings.TrimPrefix(parts[len(parts)-1], "x-")
+}
+
+// parseIfMatch - read the If-Match header into the article versions it accepts, `*` only requires the article
+// to exist, weak etags never match since If-Match uses the strong comparison. The etags of every representation
+// of a version match the version
+func parseIfMatch(header string) (mustExist bool, versions []int64, err error) {
+	if strings.TrimSpace(header) == "*" {
+		return true, nil, nil
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if !strings.HasPrefix(candidate, `"v`) || !strings.HasSuffix(candidate, `"`) {
+			continue
+		}
+		tag := strings.SplitN(strings.TrimSuffix(strings.TrimPrefix(candidate, `"v`), `"`), "-", 2)
+		version, err := strconv.ParseInt(tag[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		versions = append(versions, version)
+	}
+	if len(versions) == 0 {
+		return false, nil, fmt.Errorf("none of the etags [%s] match an article version", header)
+	}
+	return true, versions, nil
+}
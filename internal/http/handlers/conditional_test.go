@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNotModified(t *testing.T) {
+	lastModified := time.Date(2023, 3, 30, 10, 0, 0, 500, time.UTC)
+	tests := []struct {
+		name         string
+		headers      map[string]string
+		etag         string
+		lastModified time.Time
+		want         bool
+	}{
+		{name: "no_preconditions", etag: `"v1"`, lastModified: lastModified, want: false},
+		{name: "any_etag", headers: map[string]string{HeaderIfNoneMatch: "*"}, etag: `"v1"`, want: true},
+		{name: "matching_etag", headers: map[string]string{HeaderIfNoneMatch: `"v1"`}, etag: `"v1"`, want: true},
+		{name: "stale_etag", headers: map[string]string{HeaderIfNoneMatch: `"v1"`}, etag: `"v2"`, want: false},
+		{name: "weak_etag", headers: map[string]string{HeaderIfNoneMatch: `W/"v1"`}, etag: `"v1"`, want: true},
+		{name: "weak_response_etag", headers: map[string]string{HeaderIfNoneMatch: `"v1"`}, etag: `W/"v1"`, want: true},
+		{name: "etag_list", headers: map[string]string{HeaderIfNoneMatch: `"v1", W/"v2" ,"v3"`}, etag: `"v2"`, want: true},
+		{name: "etag_list_mismatch", headers: map[string]string{HeaderIfNoneMatch: `"v1", "v3"`}, etag: `"v2"`, want: false},
+		{
+			name:         "not_modified_since",
+			headers:      map[string]string{HeaderIfModifiedSince: lastModified.Format(http.TimeFormat)},
+			etag:         `"v1"`,
+			lastModified: lastModified,
+			want:         true,
+		},
+		{
+			name:         "modified_since",
+			headers:      map[string]string{HeaderIfModifiedSince: lastModified.Add(-time.Second).Format(http.TimeFormat)},
+			etag:         `"v1"`,
+			lastModified: lastModified,
+			want:         false,
+		},
+		{
+			name:         "invalid_date",
+			headers:      map[string]string{HeaderIfModifiedSince: "yesterday"},
+			etag:         `"v1"`,
+			lastModified: lastModified,
+			want:         false,
+		},
+		{
+			name:    "unknown_last_modified",
+			headers: map[string]string{HeaderIfModifiedSince: lastModified.Format(http.TimeFormat)},
+			etag:    `"v1"`,
+			want:    false,
+		},
+		{
+			// If-Modified-Since is ignored when If-None-Match is present
+			name: "if_none_match_precedence_modified",
+			headers: map[string]string{
+				HeaderIfNoneMatch:     `"v1"`,
+				HeaderIfModifiedSince: lastModified.Format(http.TimeFormat),
+			},
+			etag:         `"v2"`,
+			lastModified: lastModified,
+			want:         false,
+		},
+		{
+			name: "if_none_match_precedence_not_modified",
+			headers: map[string]string{
+				HeaderIfNoneMatch:     `"v2"`,
+				HeaderIfModifiedSince: lastModified.Add(-time.Hour).Format(http.TimeFormat),
+			},
+			etag:         `"v2"`,
+			lastModified: lastModified,
+			want:         true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/articles/1", nil)
+			for key, value := range tt.headers {
+				request.Header.Set(key, value)
+			}
+			assert.Equal(t, tt.want, notModified(request, tt.etag, tt.lastModified))
+		})
+	}
+}
+
+func TestWriteConditional(t *testing.T) {
+	lastModified := time.Date(2023, 3, 30, 10, 0, 0, 0, time.UTC)
+
+	writer := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/articles/1", nil)
+	assert.False(t, writeConditional(writer, request, `"v1"`, lastModified, "public, max-age=60"))
+	assert.Equal(t, `"v1"`, writer.Header().Get(HeaderETag))
+	assert.Equal(t, "Thu, 30 Mar 2023 10:00:00 GMT", writer.Header().Get(HeaderLastModified))
+	assert.Equal(t, "public, max-age=60", writer.Header().Get(HeaderCacheControl))
+
+	writer = httptest.NewRecorder()
+	request.Header.Set(HeaderIfNoneMatch, `"v1"`)
+	assert.True(t, writeConditional(writer, request, `"v1"`, time.Time{}, ""))
+	assert.Equal(t, http.StatusNotModified, writer.Code)
+	assert.Equal(t, `"v1"`, writer.Header().Get(HeaderETag))
+	assert.Empty(t, writer.Header().Get(HeaderLastModified))
+	assert.Empty(t, writer.Header().Get(HeaderCacheControl))
+}
+
+func TestParseIfMatch(t *testing.T) {
+	tests := []struct {
+		name          string
+		header        string
+		wantMustExist bool
+		wantVersions  []int64
+		wantErr       bool
+	}{
+		{name: "any", header: "*", wantMustExist: true},
+		{name: "single_version", header: `"v3"`, wantMustExist: true, wantVersions: []int64{3}},
+		{name: "representation_version", header: `"v3-xml"`, wantMustExist: true, wantVersions: []int64{3}},
+		{name: "version_list", header: `"v1", "v2"`, wantMustExist: true, wantVersions: []int64{1, 2}},
+		{name: "weak_etags_skipped", header: `W/"v1", "v2"`, wantMustExist: true, wantVersions: []int64{2}},
+		{name: "only_weak_etags", header: `W/"v1"`, wantErr: true},
+		{name: "not_a_version", header: `"abc"`, wantErr: true},
+		{name: "invalid_version", header: `"vx"`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mustExist, versions, err := parseIfMatch(tt.header)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantMustExist, mustExist)
+			assert.Equal(t, tt.wantVersions, versions)
+		})
+	}
+}
+
+func TestVersionETag(t *testing.T) {
+	assert.Equal(t, `"v2-json"`, versionETag(2, "application/json"))
+	assert.Equal(t, `"v2-xml"`, versionETag(2, "application/xml"))
+	assert.Equal(t, `"v2-csv"`, versionETag(2, "text/csv; charset=utf-8"))
+	assert.Equal(t, `"v2-msgpack"`, versionETag(2, "application/msgpack"))
+	assert.Equal(t, `"v2-msgpack"`, versionETag(2, "application/x-msgpack"))
+}
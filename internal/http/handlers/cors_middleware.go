@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORSMiddleware sets the CORS headers of the allowed origins and answers the preflight requests without reaching the
+// routes. Origins are matched exactly, with `*` for any origin or with a `*.` wildcard for the subdomains of a host
+// e.g. `https://*.example.com`
+type CORSMiddleware struct {
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+func (cm CORSMiddleware) MiddleFunc(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		origin := request.Header.Get("Origin")
+		if origin == "" {
+			handler.ServeHTTP(writer, request)
+			return
+		}
+		writer.Header().Add("Vary", "Origin")
+
+		preflight := request.Method == http.MethodOptions && request.Header.Get("Access-Control-Request-Method") != ""
+		if preflight {
+			writer.Header().Add("Vary", "Access-Control-Request-Method")
+			writer.Header().Add("Vary", "Access-Control-Request-Headers")
+		}
+		if !cm.allowOrigin(origin) {
+			if preflight {
+				writer.WriteHeader(http.StatusNoContent)
+				return
+			}
+			handler.ServeHTTP(writer, request)
+			return
+		}
+
+		cm.setOrigin(writer, origin)
+		if !preflight {
+			if len(cm.ExposedHeaders) > 0 {
+				writer.Header().Set("Access-Control-Expose-Headers", strings.Join(cm.ExposedHeaders, ", "))
+			}
+			handler.ServeHTTP(writer, request)
+			return
+		}
+
+		// preflight responses only allow the requested method and headers when all of them are allowed
+		method := request.Header.Get("Access-Control-Request-Method")
+		headers := splitQueryList(request.Header.Values("Access-Control-Request-Headers"))
+		if containsFold(cm.AllowedMethods, method) && cm.allowHeaders(headers) {
+			writer.Header().Set("Access-Control-Allow-Methods", strings.Join(cm.AllowedMethods, ", "))
+			if len(headers) > 0 {
+				writer.Header().Set("Access-Control-Allow-Headers", strings.Join(headers, ", "))
+			}
+			if cm.MaxAge > 0 {
+				writer.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(cm.MaxAge.Seconds())))
+			}
+		}
+		writer.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// setOrigin allow the origin, any origin is only allowed with `*` when credentials are not allowed
+func (cm CORSMiddleware) setOrigin(writer http.ResponseWriter, origin string) {
+	if containsFold(cm.AllowedOrigins, "*") && !cm.AllowCredentials {
+		writer.Header().Set("Access-Control-Allow-Origin", "*")
+		return
+	}
+	writer.Header().Set("Access-Control-Allow-Origin", origin)
+	if cm.AllowCredentials {
+		writer.Header().Set("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+// allowOrigin check if the origin matches any of the allowed origins
+func (cm CORSMiddleware) allowOrigin(origin string) bool {
+	origin = strings.ToLower(origin)
+	for _, allowed := range cm.AllowedOrigins {
+		allowed = strings.ToLower(allowed)
+		if allowed == "*" || allowed == origin {
+			return true
+		}
+		parts := strings.SplitN(allowed, "://*.", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		prefix, suffix := parts[0]+"://", "."+parts[1]
+		if strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) &&
+			len(origin) > len(prefix)+len(suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowHeaders check if all the headers are allowed
+func (cm CORSMiddleware) allowHeaders(headers []string) bool {
+	if containsFold(cm.AllowedHeaders, "*") {
+		return true
+	}
+	for _, h := range headers {
+		if !containsFold(cm.AllowedHeaders, h) {
+			return false
+		}
+	}
+	return true
+}
+
+// containsFold check if the values contain the value ignoring the case
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCORSMiddleware_allowOrigin(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []string
+		origin  string
+		want    bool
+	}{
+		{name: "exact", allowed: []string{"https://example.com"}, origin: "https://example.com", want: true},
+		{name: "exact_case_insensitive", allowed: []string{"https://Example.com"}, origin: "https://example.COM", want: true},
+		{name: "exact_other_scheme", allowed: []string{"https://example.com"}, origin: "http://example.com", want: false},
+		{name: "exact_other_port", allowed: []string{"https://example.com"}, origin: "https://example.com:8443", want: false},
+		{name: "exact_look_alike", allowed: []string{"https://example.com"}, origin: "https://evilexample.com", want: false},
+		{name: "any", allowed: []string{"*"}, origin: "https://anything.org", want: true},
+		{name: "wildcard_subdomain", allowed: []string{"https://*.example.com"}, origin: "https://app.example.com", want: true},
+		{name: "wildcard_nested_subdomain", allowed: []string{"https://*.example.com"}, origin: "https://a.b.example.com",
+			want: true},
+		{name: "wildcard_bare_domain", allowed: []string{"https://*.example.com"}, origin: "https://example.com", want: false},
+		{name: "wildcard_look_alike", allowed: []string{"https://*.example.com"}, origin: "https://evilexample.com",
+			want: false},
+		{name: "wildcard_look_alike_subdomain", allowed: []string{"https://*.example.com"},
+			origin: "https://app.evilexample.com", want: false},
+		{name: "wildcard_suffix_attack", allowed: []string{"https://*.example.com"}, origin: "https://example.com.evil.org",
+			want: false},
+		{name: "wildcard_empty_label", allowed: []string{"https://*.example.com"}, origin: "https://.example.com", want: false},
+		{name: "wildcard_other_scheme", allowed: []string{"https://*.example.com"}, origin: "http://app.example.com",
+			want: false},
+		{name: "no_allowed_origins", origin: "https://example.com", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cm := CORSMiddleware{AllowedOrigins: tt.allowed}
+			assert.Equal(t, tt.want, cm.allowOrigin(tt.origin))
+		})
+	}
+}
+
+// corsResponse response of the cors middleware to the request with the origin, the handler answers 200
+func corsResponse(cm CORSMiddleware, method, origin string, headers map[string]string) *httptest.ResponseRecorder {
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusOK)
+	})
+	request := httptest.NewRequest(method, "/articles", nil)
+	request.Header.Set("Origin", origin)
+	for key, value := range headers {
+		request.Header.Set(key, value)
+	}
+	writer := httptest.NewRecorder()
+	cm.MiddleFunc(handler).ServeHTTP(writer, request)
+	return writer
+}
+
+func TestCORSMiddleware_Credentials(t *testing.T) {
+	// any origin is answered with `*` without credentials
+	writer := corsResponse(CORSMiddleware{AllowedOrigins: []string{"*"}}, http.MethodGet, "https://app.example.com", nil)
+	assert.Equal(t, "*", writer.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, writer.Header().Get("Access-Control-Allow-Credentials"))
+
+	// credentials are never allowed along with `*`, the origin is echoed instead
+	cm := CORSMiddleware{AllowedOrigins: []string{"*"}, AllowCredentials: true}
+	writer = corsResponse(cm, http.MethodGet, "https://app.example.com", nil)
+	assert.Equal(t, "https://app.example.com", writer.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", writer.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Equal(t, "Origin", writer.Header().Get("Vary"))
+
+	// disallowed origins get no cors headers
+	cm = CORSMiddleware{AllowedOrigins: []string{"https://example.com"}, AllowCredentials: true}
+	writer = corsResponse(cm, http.MethodGet, "https://evilexample.com", nil)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.Empty(t, writer.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, writer.Header().Get("Access-Control-Allow-Credentials"))
+}
+
+func TestCORSMiddleware_Preflight(t *testing.T) {
+	cm := CORSMiddleware{
+		AllowedOrigins: []string{"https://*.example.com"},
+		AllowedMethods: []string{http.MethodGet, http.MethodPost},
+		AllowedHeaders: []string{"Content-Type", "X-API-Key"},
+		ExposedHeaders: []string{"ETag"},
+		MaxAge:         10 * time.Minute,
+	}
+	tests := []struct {
+		name        string
+		origin      string
+		method      string
+		headers     string
+		wantAllowed bool
+	}{
+		{name: "allowed", origin: "https://app.example.com", method: http.MethodPost, headers: "content-type, x-api-key",
+			wantAllowed: true},
+		{name: "allowed_without_headers", origin: "https://app.example.com", method: http.MethodGet, wantAllowed: true},
+		{name: "disallowed_method", origin: "https://app.example.com", method: http.MethodDelete, headers: "content-type"},
+		{name: "disallowed_header", origin: "https://app.example.com", method: http.MethodPost,
+			headers: "content-type, x-forwarded-for"},
+		{name: "disallowed_origin", origin: "https://example.com", method: http.MethodPost, headers: "content-type"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := map[string]string{"Access-Control-Request-Method": tt.method}
+			if tt.headers != "" {
+				headers["Access-Control-Request-Headers"] = tt.headers
+			}
+			writer := corsResponse(cm, http.MethodOptions, tt.origin, headers)
+
+			// preflights are answered without reaching the handler
+			assert.Equal(t, http.StatusNoContent, writer.Code)
+			assert.Equal(t, []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"},
+				writer.Header().Values("Vary"))
+			assert.Empty(t, writer.Header().Get("Access-Control-Expose-Headers"))
+			if !tt.wantAllowed {
+				assert.Empty(t, writer.Header().Get("Access-Control-Allow-Methods"))
+				assert.Empty(t, writer.Header().Get("Access-Control-Allow-Headers"))
+				assert.Empty(t, writer.Header().Get("Access-Control-Max-Age"))
+				return
+			}
+			assert.Equal(t, tt.origin, writer.Header().Get("Access-Control-Allow-Origin"))
+			assert.Equal(t, "GET, POST", writer.Header().Get("Access-Control-Allow-Methods"))
+			assert.Equal(t, "600", writer.Header().Get("Access-Control-Max-Age"))
+			if tt.headers != "" {
+				assert.Equal(t, tt.headers, writer.Header().Get("Access-Control-Allow-Headers"))
+			}
+		})
+	}
+
+	// simple requests of the allowed origins reach the handler with the exposed headers
+	writer := corsResponse(cm, http.MethodGet, "https://app.example.com", nil)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.Equal(t, "ETag", writer.Header().Get("Access-Control-Expose-Headers"))
+}
@@ -4,22 +4,36 @@ import (
 	"article-dispatcher/internal/domain/adaptors/logger"
 	"article-dispatcher/internal/domain/models"
 	"article-dispatcher/internal/domain/services"
+	"article-dispatcher/internal/http/auth"
+	"article-dispatcher/internal/http/idempotency"
 	"article-dispatcher/internal/http/responses"
+	"article-dispatcher/internal/http/validation"
 
-	"github.com/go-playground/validator/v10"
+	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 
-	"encoding/json"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"net/http"
+	"strings"
 	"time"
 )
 
+// maxIdempotencyKeyLength maximum length of the `Idempotency-Key` header value
+const maxIdempotencyKeyLength = 255
+
 type ArticleCreateHandler struct {
 	Log                  logger.Logger
 	ArticleService       services.ArticleService
 	ErrorHandler         ErrorHandler
 	RequestLatencyReport *prometheus.SummaryVec
+	// IdempotencyStore keeps the responses of the requests with an `Idempotency-Key`, nil disables the replays
+	IdempotencyStore idempotency.Store
+	// StrictDecoding rejects unknown fields, trailing data and bodies without a content type
+	StrictDecoding bool
 }
 
 func (ac ArticleCreateHandler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
@@ -31,45 +45,146 @@ func (ac ArticleCreateHandler) ServeHTTP(writer http.ResponseWriter, request *ht
 			Observe(float64(time.Since(start).Microseconds()))
 	}()
 
-	var article models.Article
-	err = json.NewDecoder(request.Body).Decode(&article)
-	if err != nil {
-		ac.ErrorHandler.Handle(request.Context(), writer, InvalidPayload{
-			fmt.Errorf("error decoding request body due to, %w", err)})
+	key := request.Header.Get(HeaderIdempotencyKey)
+	if key != "" && ac.IdempotencyStore != nil {
+		err = ac.createIdempotent(writer, request, key)
 		return
 	}
+	err = ac.create(writer, request)
+}
+
+// createIdempotent - create the article only once per idempotency key, duplicated requests with the same body
+// get the original response replayed while the ones with a different body are rejected
+func (ac ArticleCreateHandler) createIdempotent(writer http.ResponseWriter, request *http.Request, key string) error {
+	if len(key) > maxIdempotencyKeyLength {
+		err := ValidationError{fmt.Errorf("idempotency key cannot be longer than %d characters", maxIdempotencyKeyLength)}
+		ac.ErrorHandler.Handle(request.Context(), writer, err)
+		return err
+	}
+
+	body, err := io.ReadAll(request.Body)
+	if err != nil {
+		err = bodyError(request, err, "error reading request body")
+		ac.ErrorHandler.Handle(request.Context(), writer, err)
+		return err
+	}
+	request.Body = io.NopCloser(bytes.NewReader(body))
+
+	storeKey := idempotencyStoreKey(request, key)
+	recorded, err := ac.IdempotencyStore.Begin(storeKey, fingerprint(request, body))
+	if err != nil {
+		switch {
+		case errors.Is(err, idempotency.ErrKeyReused):
+			err = IdempotencyKeyReused{fmt.Errorf("idempotency key [%s] was used with a different request, %w", key, err)}
+		case errors.Is(err, idempotency.ErrInProgress):
+			err = IdempotencyInProgress{fmt.Errorf("idempotency key [%s] request is still in progress, %w", key, err)}
+		}
+		ac.ErrorHandler.Handle(request.Context(), writer, err)
+		return err
+	}
+
+	// replay the original response
+	if recorded != nil {
+		writer.Header().Set(HeaderIdempotentReplayed, "true")
+		if err = writeResponse(writer, *recorded); err != nil {
+			logger.FromContext(request.Context(), ac.Log).Error("error writing to response", logger.F("error", err))
+		}
+		return nil
+	}
+
+	// server errors are not recorded so that the request can be retried, the key is also released when the create
+	// panics so that it is not left in progress until it expires
+	completed := false
+	defer func() {
+		if !completed {
+			ac.IdempotencyStore.Release(storeKey)
+		}
+	}()
+	recorder := newResponseRecorder()
+	createErr := ac.create(recorder, request)
+	if recorder.statusCode < http.StatusInternalServerError {
+		ac.IdempotencyStore.Complete(storeKey, recorder.response())
+		completed = true
+	}
+
+	if err = writeResponse(writer, recorder.response()); err != nil {
+		logger.FromContext(request.Context(), ac.Log).Error("error writing to response", logger.F("error", err))
+	}
+	return createErr
+}
+
+// create - decode, validate and create the article writing the response, the article is decoded from a json,
+// form or multipart body
+func (ac ArticleCreateHandler) create(writer http.ResponseWriter, request *http.Request) error {
+	article, err := decodeArticle(request, ac.StrictDecoding)
+	if err != nil {
+		ac.ErrorHandler.Handle(request.Context(), writer, err)
+		return err
+	}
 
 	// validate request struct
 	if err = validate(&article); err != nil {
-		ac.ErrorHandler.Handle(request.Context(), writer, ValidationError{
-			fmt.Errorf("invalid request body due to, %w", err)})
-		return
+		ac.ErrorHandler.Handle(request.Context(), writer, err)
+		return err
 	}
 
 	// call service to create the article
 	err = ac.ArticleService.Create(request.Context(), &article)
 	if err != nil {
-		ac.ErrorHandler.Handle(request.Context(), writer, fmt.Errorf("error creating article with, %w", err))
-		return
+		err = fmt.Errorf("error creating article with, %w", err)
+		ac.ErrorHandler.Handle(request.Context(), writer, err)
+		return err
 	}
 
 	resp := responses.SuccessResponse{}
 	resp.Data.ID = article.Id
-	r, err := json.Marshal(resp)
+	r, contentType, err := encodeResponse(request.Context(), resp)
 	if err != nil {
-
-		ac.ErrorHandler.Handle(request.Context(), writer,
-			ResponseMarshalError{fmt.Errorf("error marshaling response data, %w", err)})
+		ac.ErrorHandler.Handle(request.Context(), writer, err)
+		return err
 	}
-	writer.Header().Add("Content-Type", "application/json")
+	writer.Header().Set(HeaderETag, versionETag(article.Version, contentType))
+	writer.Header().Add("Content-Type", contentType)
 	writer.WriteHeader(http.StatusCreated)
 	_, err = writer.Write(r)
 	if err != nil {
-		ac.Log.Error(fmt.Sprintf("error writing to response due to, %s", err))
+		logger.FromContext(request.Context(), ac.Log).Error("error writing to response", logger.F("error", err))
+	}
+	return nil
+}
+
+// idempotencyStoreKey - idempotency key scoped to the authenticated identity, so that clients reusing the same key
+// do not get the responses of each other
+func idempotencyStoreKey(request *http.Request, key string) string {
+	if identity, ok := request.Context().Value(ParamIdentity).(auth.Identity); ok {
+		return "identity:" + identity.ID + "|" + key
 	}
+	return key
 }
 
-// validate - income request validator for the article payload
+// fingerprint - fingerprint of the request method, path and body to detect reused idempotency keys
+func fingerprint(request *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(request.Method + " " + request.URL.Path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// payloadValidator - validator of the request payloads shared by the handlers
+var payloadValidator = validation.New()
+
+// validate - income request validator for the article payload, failed rules are returned as field errors
 func validate(article *models.Article) error {
-	return validator.New().Struct(article)
+	fields, err := payloadValidator.Struct(article)
+	if err != nil {
+		return fmt.Errorf("error validating request body due to, %w", err)
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	messages := make([]string, len(fields))
+	for i, f := range fields {
+		messages[i] = f.Message
+	}
+	return FieldErrors{fmt.Errorf("invalid request body, %s", strings.Join(messages, ", ")), fields}
 }
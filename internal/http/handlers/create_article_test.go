@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"article-dispatcher/internal/domain/models"
+	"article-dispatcher/internal/domain/services"
+	"article-dispatcher/internal/http/auth"
+	"article-dispatcher/internal/http/idempotency"
+
+	"github.com/stretchr/testify/assert"
+
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testArticleBody = `{"title": "test", "date": "2023-03-30", "body": "test body", "tags": ["fun"]}`
+
+// createService article service creating the articles with sequential ids, or panicking when set
+type createService struct {
+	services.ArticleService
+	created int
+	panics  bool
+}
+
+func (s *createService) Create(_ context.Context, article *models.Article) error {
+	if s.panics {
+		panic("create failed")
+	}
+	s.created++
+	article.Id = fmt.Sprint(s.created)
+	article.Version = 1
+	return nil
+}
+
+// idempotentRequest create request with the idempotency key, authenticated as the identity when set
+func idempotentRequest(key, identity string) *http.Request {
+	request := httptest.NewRequest(http.MethodPost, "/articles", strings.NewReader(testArticleBody))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(HeaderIdempotencyKey, key)
+	if identity != "" {
+		ctx := context.WithValue(request.Context(), ParamIdentity, auth.Identity{ID: identity})
+		request = request.WithContext(ctx)
+	}
+	return request
+}
+
+func TestArticleCreateHandler_createIdempotent(t *testing.T) {
+	service := &createService{}
+	ac := ArticleCreateHandler{ArticleService: service, IdempotencyStore: idempotency.NewMemoryStore(time.Hour)}
+
+	// the duplicated request of the same client is replayed
+	first := httptest.NewRecorder()
+	assert.NoError(t, ac.createIdempotent(first, idempotentRequest("1", "alice"), "1"))
+	replayed := httptest.NewRecorder()
+	assert.NoError(t, ac.createIdempotent(replayed, idempotentRequest("1", "alice"), "1"))
+	assert.Equal(t, http.StatusCreated, replayed.Code)
+	assert.Equal(t, "true", replayed.Header().Get(HeaderIdempotentReplayed))
+	assert.Equal(t, first.Body.String(), replayed.Body.String())
+	assert.Equal(t, 1, service.created)
+
+	// another client reusing the key creates its own article
+	other := httptest.NewRecorder()
+	assert.NoError(t, ac.createIdempotent(other, idempotentRequest("1", "bob"), "1"))
+	assert.Equal(t, http.StatusCreated, other.Code)
+	assert.Empty(t, other.Header().Get(HeaderIdempotentReplayed))
+	assert.NotEqual(t, first.Body.String(), other.Body.String())
+	assert.Equal(t, 2, service.created)
+}
+
+func TestArticleCreateHandler_createIdempotentPanic(t *testing.T) {
+	store := idempotency.NewMemoryStore(time.Hour)
+	ac := ArticleCreateHandler{ArticleService: &createService{panics: true}, IdempotencyStore: store}
+
+	assert.Panics(t, func() {
+		_ = ac.createIdempotent(httptest.NewRecorder(), idempotentRequest("1", "alice"), "1")
+	})
+
+	// the key is released, so that the request can be retried
+	recorded, err := store.Begin(idempotencyStoreKey(idempotentRequest("1", "alice"), "1"), "retry")
+	assert.NoError(t, err)
+	assert.Nil(t, recorded)
+}
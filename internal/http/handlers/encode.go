@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"article-dispatcher/internal/http/encoders"
+
+	"github.com/pkg/errors"
+
+	"context"
+	"fmt"
+)
+
+// acceptedEncoders - encoders negotiated for the request ordered by the client preference,
+// JSON when the request was not negotiated
+func acceptedEncoders(ctx context.Context) []encoders.Encoder {
+	accepted, ok := ctx.Value(ParamEncoders).([]encoders.Encoder)
+	if !ok {
+		return []encoders.Encoder{encoders.JSON{}}
+	}
+	return accepted
+}
+
+// encodeResponse - encode the response payload with the first negotiated encoder supporting the payload,
+// returns the encoded body along with its content type
+func encodeResponse(ctx context.Context, v interface{}) ([]byte, string, error) {
+	for _, encoder := range acceptedEncoders(ctx) {
+		body, err := encoder.Encode(v)
+		if errors.Is(err, encoders.ErrUnsupportedPayload) {
+			continue
+		}
+		if err != nil {
+			return nil, "", ResponseMarshalError{fmt.Errorf("error marshaling response data, %w", err)}
+		}
+		return body, encoder.ContentType(), nil
+	}
+	return nil, "", NotAcceptable{fmt.Errorf("response cannot be represented in any of the accepted media types")}
+}
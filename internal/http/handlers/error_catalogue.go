@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"article-dispatcher/internal/domain/adaptors/logger"
+	domErrors "article-dispatcher/internal/domain/errors"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ErrorCatalogueHandler serves the internal error codes, all of them or the one of the `code` path parameter
+type ErrorCatalogueHandler struct {
+	Log                  logger.Logger
+	ErrorHandler         ErrorHandler
+	RequestLatencyReport *prometheus.SummaryVec
+}
+
+func (ec ErrorCatalogueHandler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	start := time.Now()
+	var err error
+	defer func() {
+		ec.RequestLatencyReport.
+			With(map[string]string{"endpoint": "error_catalogue", "error": fmt.Sprintf(`%t`, err != nil)}).
+			Observe(float64(time.Since(start).Microseconds()))
+	}()
+
+	var payload interface{} = errorCatalogue
+	if param, ok := mux.Vars(request)[PathParameterErrorCode]; ok {
+		code, convErr := strconv.Atoi(param)
+		if convErr != nil {
+			err = ValidationError{fmt.Errorf("invalid error code [%s]", param)}
+			ec.ErrorHandler.Handle(request.Context(), writer, err)
+			return
+		}
+		entry, found := errorCodes[code]
+		if !found {
+			err = fmt.Errorf("error code [%d] is %w", code, domErrors.ErrNotFound)
+			ec.ErrorHandler.Handle(request.Context(), writer, err)
+			return
+		}
+		payload = entry
+	}
+
+	r, contentType, err := encodeResponse(request.Context(), payload)
+	if err != nil {
+		ec.ErrorHandler.Handle(request.Context(), writer, err)
+		return
+	}
+	writer.Header().Add("Content-Type", contentType)
+	writer.WriteHeader(http.StatusOK)
+	_, err = writer.Write(r)
+	if err != nil {
+		logger.FromContext(request.Context(), ec.Log).Error("error writing to response", logger.F("error", err))
+	}
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"article-dispatcher/internal/http/responses"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// declaredErrorCodes - internal error codes declared in internal_error_codes.go
+func declaredErrorCodes(t *testing.T) map[string]int {
+	file, err := parser.ParseFile(token.NewFileSet(), "internal_error_codes.go", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	codes := make(map[string]int)
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			value := spec.(*ast.ValueSpec)
+			for i, name := range value.Names {
+				code, err := strconv.Atoi(value.Values[i].(*ast.BasicLit).Value)
+				if err != nil {
+					t.Fatal(err)
+				}
+				codes[name.Name] = code
+			}
+		}
+	}
+	return codes
+}
+
+func TestErrorCatalogue(t *testing.T) {
+	declared := declaredErrorCodes(t)
+	assert.NotEmpty(t, declared)
+	assert.Len(t, errorCatalogue, len(declared), "every declared code is listed once")
+	for name, code := range declared {
+		entry, found := errorCodes[code]
+		if assert.True(t, found, name) {
+			assert.NotZero(t, entry.Status, name)
+			assert.NotEmpty(t, entry.Title, name)
+			assert.NotEmpty(t, entry.Description, name)
+		}
+	}
+}
+
+func TestErrorCatalogueHandler(t *testing.T) {
+	ec := ErrorCatalogueHandler{
+		ErrorHandler: testErrorHandler(t),
+		RequestLatencyReport: prometheus.NewSummaryVec(prometheus.SummaryOpts{Name: "test_latency"},
+			[]string{"endpoint", "error"}),
+	}
+
+	t.Run("catalogue", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		ec.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/errors", nil))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		var catalogue []responses.ErrorCode
+		assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &catalogue))
+		assert.Equal(t, errorCatalogue, catalogue)
+	})
+
+	tests := []struct {
+		name       string
+		code       string
+		wantStatus int
+		wantCode   int
+	}{
+		{name: "code", code: strconv.Itoa(VersionMismatchError), wantStatus: http.StatusOK, wantCode: VersionMismatchError},
+		{name: "unknown_code", code: "49999", wantStatus: http.StatusNotFound, wantCode: NotFoundError},
+		{name: "invalid_code", code: "abc", wantStatus: http.StatusBadRequest, wantCode: InvalidRequestError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/errors/"+tt.code, nil),
+				map[string]string{PathParameterErrorCode: tt.code})
+			recorder := httptest.NewRecorder()
+			ec.ServeHTTP(recorder, request)
+			assert.Equal(t, tt.wantStatus, recorder.Code)
+			var body struct {
+				Code int `json:"code"`
+			}
+			assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+			assert.Equal(t, tt.wantCode, body.Code)
+		})
+	}
+}
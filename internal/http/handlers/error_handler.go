@@ -1,11 +1,11 @@
 package handlers
 
 import (
-	"article-dispatcher/internal/adaptors/cache"
 	"article-dispatcher/internal/domain/adaptors/logger"
+	domErrors "article-dispatcher/internal/domain/errors"
+	"article-dispatcher/internal/http/encoders"
 	"article-dispatcher/internal/http/responses"
 
-	"github.com/google/uuid"
 	"github.com/pkg/errors"
 
 	"context"
@@ -14,31 +14,80 @@ import (
 	"net/http"
 )
 
+// defaultProblemTypeBase base of the problem details type URIs, resolved against the request URI
+const defaultProblemTypeBase = "/errors/"
+
 type ErrorHandler struct {
 	Log logger.Logger
+	// ProblemTypeBase base of the problem details type URIs, followed by the internal error code
+	ProblemTypeBase string
 }
 
 type internalErrorFields struct {
 	code           int
 	httpStatusCode int
 	trace          string
+	fields         []responses.FieldError
 }
 
 // Handle - error handling
 func (e *ErrorHandler) Handle(ctx context.Context, writer http.ResponseWriter, err error) {
-	e.Log.Error(fmt.Sprintf("error executing request due to : %s", err.Error()))
+	e.log(ctx).Error("error executing request", logger.F("error", err))
 	errorBody := e.createErrorResponse(ctx, err)
-	bodyByt, err := json.Marshal(errorBody)
+	if problem, ok := ctx.Value(ParamProblemDetails).(bool); ok && problem {
+		e.writeProblem(ctx, writer, errorBody)
+		return
+	}
+	bodyByt, contentType, err := encodeResponse(ctx, errorBody)
 	if err != nil {
-		e.Log.Error(fmt.Sprintf(`failed to encode error response with, %s`, err))
+		// error responses fall back to json when they cannot be represented in the accepted media types
+		contentType = encoders.JSON{}.ContentType()
+		bodyByt, err = encoders.JSON{}.Encode(errorBody)
+		if err != nil {
+			e.log(ctx).Error("failed to encode error response", logger.F("error", err))
+		}
 	}
 
-	writer.Header().Add("Content-Type", "application/json")
+	writer.Header().Add("Content-Type", contentType)
 	writer.WriteHeader(errorBody.StatusCode)
 	_, err = writer.Write(bodyByt)
 	if err != nil {
-		e.Log.Error(fmt.Sprintf(`failed to write error response due to, %s`, err))
+		e.log(ctx).Error("failed to write error response", logger.F("error", err))
+	}
+}
+
+// writeProblem - write the error response as RFC 7807 problem details
+func (e *ErrorHandler) writeProblem(ctx context.Context, writer http.ResponseWriter, errorBody responses.ErrorResponse) {
+	typeBase := e.ProblemTypeBase
+	if typeBase == "" {
+		typeBase = defaultProblemTypeBase
+	}
+	title := errorCodes[errorBody.Code].Title
+	instance, _ := ctx.Value(ParamRequestPath).(string)
+	bodyByt, err := json.Marshal(responses.ProblemDetails{
+		Type:     fmt.Sprintf("%s%d", typeBase, errorBody.Code),
+		Title:    title,
+		Status:   errorBody.StatusCode,
+		Detail:   errorBody.Description,
+		Instance: instance,
+		Code:     errorBody.Code,
+		Trace:    errorBody.Trace,
+		Errors:   errorBody.Errors,
+	})
+	if err != nil {
+		e.log(ctx).Error("failed to encode problem details", logger.F("error", err))
 	}
+
+	writer.Header().Add("Content-Type", MediaTypeProblemJSON)
+	writer.WriteHeader(errorBody.StatusCode)
+	if _, err = writer.Write(bodyByt); err != nil {
+		e.log(ctx).Error("failed to write error response", logger.F("error", err))
+	}
+}
+
+// log logger of the request, falling back to the logger of the handler
+func (e *ErrorHandler) log(ctx context.Context) logger.Logger {
+	return logger.FromContext(ctx, e.Log)
 }
 
 func (e *ErrorHandler) createErrorResponse(ctx context.Context, err error) responses.ErrorResponse {
@@ -47,46 +96,99 @@ func (e *ErrorHandler) createErrorResponse(ctx context.Context, err error) respo
 		StatusCode:  errorFields.httpStatusCode,
 		Code:        errorFields.code,
 		Description: errorFields.trace,
-		Trace:       ctx.Value(ParamTraceID).(uuid.UUID).String(),
+		Trace:       requestID(ctx),
+		Errors:      errorFields.fields,
 	}
 }
 
-// mapError - mapp all the errors in the service into internal error codes
+// domainErrorCodes internal error codes of the domain error kinds, refined kinds are listed before their parents
+var domainErrorCodes = []struct {
+	kind error
+	code int
+}{
+	{kind: domErrors.ErrAlreadyExists, code: PreconditionFailedError},
+	{kind: domErrors.ErrVersionMismatch, code: VersionMismatchError},
+	{kind: domErrors.ErrNotFound, code: NotFoundError},
+	{kind: domErrors.ErrInvalid, code: InvalidRequestDataError},
+	{kind: domErrors.ErrConflict, code: DataConflictError},
+	{kind: domErrors.ErrUnavailable, code: UnavailableError},
+	{kind: domErrors.ErrInternal, code: InternalError},
+}
+
+// mapError - map the errors into internal error codes, the handler errors by their type and the service errors by
+// their domain error kind. Errors which cannot be mapped are internal server errors
 func mapError(err error) internalErrorFields {
-	switch err.(type) {
-	case cache.InvalidDataError:
-		return internalErrorFields{
-			code:           InvalidRequestDataError,
-			httpStatusCode: http.StatusBadRequest,
-			trace:          err.Error(),
-		}
-	case cache.DataNotFoundError:
-		return internalErrorFields{
-			code:           InvalidPayloadError,
-			httpStatusCode: http.StatusNotFound,
-			trace:          err.Error(),
-		}
-	case InvalidPayload:
-		return internalErrorFields{
-			code:           InvalidPayloadError,
-			httpStatusCode: http.StatusBadRequest,
-			trace:          err.Error(),
+	if code, ok := handlerErrorCode(err); ok {
+		return newErrorFields(code, err)
+	}
+	for _, d := range domainErrorCodes {
+		if errors.Is(err, d.kind) {
+			return newErrorFields(d.code, err)
 		}
+	}
+	if unwrapped := errors.Unwrap(err); unwrapped != nil {
+		return mapError(unwrapped)
+	}
+	return internalErrorFields{
+		code:           UnknownError,
+		httpStatusCode: http.StatusInternalServerError,
+		trace:          "something went wrong.",
+	}
+}
 
-	case ValidationError:
-		return internalErrorFields{
-			code:           InvalidRequestError,
-			httpStatusCode: http.StatusBadRequest,
-			trace:          err.Error(),
-		}
+// newErrorFields - error fields of the internal error code with the http status of the error catalogue
+func newErrorFields(code int, err error) internalErrorFields {
+	fields := internalErrorFields{
+		code:           code,
+		httpStatusCode: errorCodes[code].Status,
+		trace:          err.Error(),
+	}
+	if fe, ok := err.(FieldErrors); ok {
+		fields.fields = fe.Fields
+	}
+	return fields
+}
+
+// handlerErrorCode - internal error code of the handler errors
+func handlerErrorCode(err error) (int, bool) {
+	switch err.(type) {
+	case VersionMismatch:
+		return VersionMismatchError, true
+	case IdempotencyKeyReused:
+		return IdempotencyKeyReusedError, true
+	case IdempotencyInProgress:
+		return IdempotencyInProgressError, true
+	case UnsupportedContentEncoding:
+		return UnsupportedEncodingError, true
+	case Unauthenticated:
+		return UnauthenticatedError, true
+	case InvalidToken:
+		return InvalidTokenError, true
+	case ExpiredToken:
+		return ExpiredTokenError, true
+	case InvalidTokenClaims:
+		return InvalidTokenClaimsError, true
+	case Forbidden:
+		return ForbiddenError, true
+	case RateLimited:
+		return RateLimitedError, true
+	case PayloadTooLarge:
+		return PayloadTooLargeError, true
+	case UnknownField:
+		return UnknownFieldError, true
+	case TrailingData:
+		return TrailingDataError, true
+	case MissingContentType:
+		return MissingContentTypeError, true
+	case UnsupportedMediaType:
+		return UnsupportedMediaTypeError, true
+	case NotAcceptable:
+		return NotAcceptableError, true
+	case InvalidPayload:
+		return InvalidPayloadError, true
+	case FieldErrors, ValidationError:
+		return InvalidRequestError, true
 	default:
-		if errors.Unwrap(err) == nil {
-			return internalErrorFields{
-				code:           UnknownError,
-				httpStatusCode: http.StatusBadRequest,
-				trace:          "something went wrong.",
-			}
-		}
-		return mapError(errors.Unwrap(err))
+		return 0, false
 	}
 }
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	domErrors "article-dispatcher/internal/domain/errors"
+	"article-dispatcher/internal/http/responses"
+	"article-dispatcher/internal/pkg/tracing"
+
+	"github.com/stretchr/testify/assert"
+
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestErrorHandler_writeProblem(t *testing.T) {
+	ctx := context.WithValue(context.Background(), ParamProblemDetails, true)
+	ctx = context.WithValue(ctx, ParamRequestPath, "/articles")
+	ctx = tracing.WithRequestID(ctx, "b7a2ff5b-f2ab-465c-8ef0-fdcdbf9ed030")
+	fields := []responses.FieldError{{Field: "title", Rule: "required", Message: "title is required"}}
+	err := FieldErrors{error: fmt.Errorf("invalid article"), Fields: fields}
+
+	tests := []struct {
+		name         string
+		typeBase     string
+		wantTypeBase string
+	}{
+		{name: "default_type_base", wantTypeBase: "/errors/"},
+		{name: "type_base", typeBase: "https://example.com/problems/", wantTypeBase: "https://example.com/problems/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := testErrorHandler(t)
+			e.ProblemTypeBase = tt.typeBase
+			recorder := httptest.NewRecorder()
+			e.Handle(ctx, recorder, err)
+
+			assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			assert.Equal(t, MediaTypeProblemJSON, recorder.Header().Get("Content-Type"))
+			var problem responses.ProblemDetails
+			assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &problem))
+			assert.Equal(t, responses.ProblemDetails{
+				Type:     fmt.Sprintf("%s%d", tt.wantTypeBase, InvalidRequestError),
+				Title:    errorCodes[InvalidRequestError].Title,
+				Status:   http.StatusBadRequest,
+				Detail:   "invalid article",
+				Instance: "/articles",
+				Code:     InvalidRequestError,
+				Trace:    "b7a2ff5b-f2ab-465c-8ef0-fdcdbf9ed030",
+				Errors:   fields,
+			}, problem)
+		})
+	}
+}
+
+func TestMapError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantCode   int
+		wantStatus int
+	}{
+		{name: "not_found", err: fmt.Errorf("fetching article, %w", domErrors.ErrNotFound),
+			wantCode: NotFoundError, wantStatus: http.StatusNotFound},
+		{name: "invalid", err: fmt.Errorf("storing article, %w", domErrors.ErrInvalid),
+			wantCode: InvalidRequestDataError, wantStatus: http.StatusBadRequest},
+		{name: "conflict", err: fmt.Errorf("storing article, %w", domErrors.ErrConflict),
+			wantCode: DataConflictError, wantStatus: http.StatusConflict},
+		// the refined kinds win over their conflict parent
+		{name: "already_exists", err: fmt.Errorf("storing article, %w", domErrors.ErrAlreadyExists),
+			wantCode: PreconditionFailedError, wantStatus: http.StatusPreconditionFailed},
+		{name: "version_mismatch", err: fmt.Errorf("storing article, %w", domErrors.ErrVersionMismatch),
+			wantCode: VersionMismatchError, wantStatus: http.StatusPreconditionFailed},
+		{name: "unavailable", err: fmt.Errorf("fetching article, %w", domErrors.ErrUnavailable),
+			wantCode: UnavailableError, wantStatus: http.StatusServiceUnavailable},
+		{name: "internal", err: fmt.Errorf("fetching article, %w", domErrors.ErrInternal),
+			wantCode: InternalError, wantStatus: http.StatusInternalServerError},
+		{name: "handler_error", err: PayloadTooLarge{errors.New("too large")},
+			wantCode: PayloadTooLargeError, wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "wrapped_handler_error", err: fmt.Errorf("decoding, %w", UnknownField{errors.New("unknown field")}),
+			wantCode: UnknownFieldError, wantStatus: http.StatusBadRequest},
+		{name: "unknown", err: errors.New("something failed"), wantCode: UnknownError, wantStatus: http.StatusInternalServerError},
+		{name: "wrapped_unknown", err: fmt.Errorf("fetching article, %w", errors.New("something failed")),
+			wantCode: UnknownError, wantStatus: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertErrorCode(t, tt.err, tt.wantCode, tt.wantStatus)
+		})
+	}
+}
+
+func TestDomainErrorCodes(t *testing.T) {
+	// each kind is mapped, and before the kinds it refines so that the most refined one wins
+	for i, d := range domainErrorCodes {
+		_, found := errorCodes[d.code]
+		assert.True(t, found, d.kind.Error())
+		for _, parent := range domainErrorCodes[:i] {
+			assert.False(t, errors.Is(d.kind, parent.kind), "%s is listed after its parent %s", d.kind, parent.kind)
+		}
+	}
+}
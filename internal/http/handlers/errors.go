@@ -1,5 +1,9 @@
 package handlers
 
+import (
+	"article-dispatcher/internal/http/responses"
+)
+
 type InvalidPayload struct {
 	error
 }
@@ -11,3 +15,73 @@ type ValidationError struct {
 type ResponseMarshalError struct {
 	error
 }
+
+type IdempotencyKeyReused struct {
+	error
+}
+
+type IdempotencyInProgress struct {
+	error
+}
+
+type VersionMismatch struct {
+	error
+}
+
+type UnsupportedContentEncoding struct {
+	error
+}
+
+type NotAcceptable struct {
+	error
+}
+
+type UnsupportedMediaType struct {
+	error
+}
+
+type Unauthenticated struct {
+	error
+}
+
+type Forbidden struct {
+	error
+}
+
+type InvalidToken struct {
+	error
+}
+
+type ExpiredToken struct {
+	error
+}
+
+type InvalidTokenClaims struct {
+	error
+}
+
+type RateLimited struct {
+	error
+}
+
+type PayloadTooLarge struct {
+	error
+}
+
+type UnknownField struct {
+	error
+}
+
+type TrailingData struct {
+	error
+}
+
+type MissingContentType struct {
+	error
+}
+
+// FieldErrors validation error with the failed rules of the request fields
+type FieldErrors struct {
+	error
+	Fields []responses.FieldError
+}
@@ -2,27 +2,45 @@ package handlers
 
 import (
 	"article-dispatcher/internal/domain/adaptors/logger"
+	"article-dispatcher/internal/domain/models"
 	"article-dispatcher/internal/domain/services"
+	"article-dispatcher/internal/http/responses"
 
 	"github.com/gorilla/mux"
 	"github.com/prometheus/client_golang/prometheus"
 
-	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 	"time"
 )
 
+// articleFields - selectable article fields with their value getters, keyed by the json field name
+var articleFields = map[string]func(article models.Article) interface{}{
+	"id":          func(article models.Article) interface{} { return article.Id },
+	"title":       func(article models.Article) interface{} { return article.Title },
+	"date":        func(article models.Article) interface{} { return article.Date },
+	"body":        func(article models.Article) interface{} { return article.Body },
+	"tags":        func(article models.Article) interface{} { return article.Tags },
+	"source":      func(article models.Article) interface{} { return article.Source },
+	"external_id": func(article models.Article) interface{} { return article.ExternalID },
+	"version":     func(article models.Article) interface{} { return article.Version },
+}
+
 type ArticleFilterHandler struct {
 	Log                  logger.Logger
 	ArticleService       services.ArticleService
 	ErrorHandler         ErrorHandler
 	RequestLatencyReport *prometheus.SummaryVec
+	// CacheControl value of the Cache-Control response header, empty omits the header
+	CacheControl string
 }
 
 // ServeHTTP return a success response with the tagged article payload,
-// if errors occur it will be sent to the error handler
+// with `expand=articles` the full articles are embedded instead of the ids and `fields` selects the embedded
+// article fields, if errors occur it will be sent to the error handler
 func (af ArticleFilterHandler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
 	start := time.Now()
 	var err error
@@ -51,25 +69,108 @@ func (af ArticleFilterHandler) ServeHTTP(writer http.ResponseWriter, request *ht
 		af.ErrorHandler.Handle(request.Context(), writer, err)
 		return
 	}
-	taggedArticles, err := af.ArticleService.Filter(request.Context(), articleTag, date)
+	// validate expand and fields query options
+	expand, fields, err := filterOptions(request.URL.Query())
+	if err != nil {
+		af.ErrorHandler.Handle(request.Context(), writer, ValidationError{err})
+		return
+	}
+
+	var taggedArticles interface{}
+	var lastModified time.Time
+	if expand {
+		taggedArticles, lastModified, err = af.expandedArticles(request, articleTag, date, fields)
+	} else {
+		var tagged models.TaggedArticles
+		tagged, err = af.ArticleService.Filter(request.Context(), articleTag, date)
+		taggedArticles, lastModified = tagged, tagged.LastModified
+	}
 	if err != nil {
 		err = fmt.Errorf("error fetching tagged articles data due to, %w", err)
 		af.ErrorHandler.Handle(request.Context(), writer, err)
 		return
 	}
 
-	r, err := json.Marshal(taggedArticles)
+	r, contentType, err := encodeResponse(request.Context(), taggedArticles)
 	if err != nil {
-		af.ErrorHandler.Handle(request.Context(), writer,
-			ResponseMarshalError{fmt.Errorf("error marshaling response data, %w", err)})
+		af.ErrorHandler.Handle(request.Context(), writer, err)
+		return
+	}
+	if writeConditional(writer, request, strongETag(r), lastModified, af.CacheControl) {
 		return
 	}
-	writer.Header().Add("Content-Type", "application/json")
+	writer.Header().Add("Content-Type", contentType)
 	writer.WriteHeader(http.StatusOK)
 	_, err = writer.Write(r)
 	if err != nil {
-		af.Log.Error(fmt.Sprintf("error writing to response due to, %s", err))
+		logger.FromContext(request.Context(), af.Log).Error("error writing to response", logger.F("error", err))
+	}
+}
+
+// expandedArticles - fetch the tagged articles with the full articles embedded, projected into the selected
+// fields if any, along with the last modified time of the tagged articles
+func (af ArticleFilterHandler) expandedArticles(request *http.Request, tag string, date int, fields []string) (
+	interface{}, time.Time, error) {
+	expandedArticles, err := af.ArticleService.FilterExpanded(request.Context(), tag, date)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	if len(fields) == 0 {
+		return expandedArticles, expandedArticles.LastModified, nil
+	}
+
+	projected := responses.ProjectedTaggedArticles{
+		Tag:         expandedArticles.Tag,
+		Count:       expandedArticles.Count,
+		Articles:    make([]map[string]interface{}, 0, len(expandedArticles.Articles)),
+		RelatedTags: expandedArticles.RelatedTags,
+	}
+	for _, article := range expandedArticles.Articles {
+		selected := make(map[string]interface{}, len(fields))
+		for _, field := range fields {
+			selected[field] = articleFields[field](article)
+		}
+		projected.Articles = append(projected.Articles, selected)
+	}
+
+	return projected, expandedArticles.LastModified, nil
+}
+
+// filterOptions - read and validate the `expand` and `fields` query parameters,
+// fields can only be selected when the articles are expanded
+func filterOptions(query url.Values) (expand bool, fields []string, err error) {
+	for _, value := range splitQueryList(query[QueryParameterExpand]) {
+		if value != ExpandArticles {
+			return false, nil, fmt.Errorf("invalid expand option [%s]", value)
+		}
+		expand = true
+	}
+
+	fields = splitQueryList(query[QueryParameterFields])
+	for _, field := range fields {
+		if _, ok := articleFields[field]; !ok {
+			return false, nil, fmt.Errorf("invalid article field [%s]", field)
+		}
+	}
+	if len(fields) > 0 && !expand {
+		return false, nil, fmt.Errorf("fields can only be selected with expand=%s", ExpandArticles)
+	}
+
+	return expand, fields, nil
+}
+
+// splitQueryList - split repeated and comma separated query parameter values into a single list
+func splitQueryList(values []string) []string {
+	list := make([]string, 0)
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			item = strings.TrimSpace(item)
+			if item != "" {
+				list = append(list, item)
+			}
+		}
 	}
+	return list
 }
 
 func validatePathDate(date string) bool {
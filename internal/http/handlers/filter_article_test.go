@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"article-dispatcher/internal/domain/models"
+	"article-dispatcher/internal/domain/services"
+	"article-dispatcher/internal/http/responses"
+
+	"github.com/stretchr/testify/assert"
+
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// expandedService article service returning the tagged articles
+type expandedService struct {
+	services.ArticleService
+	expanded models.ExpandedTaggedArticles
+}
+
+func (s expandedService) FilterExpanded(_ context.Context, _ string, _ int) (models.ExpandedTaggedArticles, error) {
+	return s.expanded, nil
+}
+
+func TestArticleFields(t *testing.T) {
+	// every json field of the article can be selected
+	articleType := reflect.TypeOf(models.Article{})
+	for i := 0; i < articleType.NumField(); i++ {
+		name := strings.Split(articleType.Field(i).Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		_, ok := articleFields[name]
+		assert.True(t, ok, "article field [%s] cannot be selected", name)
+	}
+}
+
+func TestArticleFilterHandler_expandedArticles(t *testing.T) {
+	article := models.Article{
+		Id:         "cms-1",
+		Title:      "test",
+		Date:       "2023-03-30",
+		Body:       "test body",
+		Tags:       []string{"fun", "health"},
+		Source:     "cms",
+		ExternalID: "a1",
+		Version:    3,
+	}
+	af := ArticleFilterHandler{ArticleService: expandedService{expanded: models.ExpandedTaggedArticles{
+		Tag:         "fun",
+		Count:       2,
+		Articles:    models.Articles{article},
+		RelatedTags: []string{"health"},
+	}}}
+
+	tests := []struct {
+		name    string
+		query   string
+		want    []map[string]interface{}
+		wantErr bool
+	}{
+		{
+			name:  "content_fields",
+			query: "expand=articles&fields=id,title",
+			want:  []map[string]interface{}{{"id": "cms-1", "title": "test"}},
+		},
+		{
+			name:  "provenance_and_version",
+			query: "expand=articles&fields=id&fields=source,external_id,version",
+			want: []map[string]interface{}{
+				{"id": "cms-1", "source": "cms", "external_id": "a1", "version": int64(3)},
+			},
+		},
+		{
+			name:  "every_field",
+			query: "expand=articles&fields=id,title,date,body,tags,source,external_id,version",
+			want: []map[string]interface{}{{
+				"id": "cms-1", "title": "test", "date": "2023-03-30", "body": "test body",
+				"tags": []string{"fun", "health"}, "source": "cms", "external_id": "a1", "version": int64(3),
+			}},
+		},
+		{name: "unknown_field", query: "expand=articles&fields=id,stored_at", wantErr: true},
+		{name: "fields_without_expand", query: "fields=id", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			assert.NoError(t, err)
+			expand, fields, err := filterOptions(query)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, expand)
+
+			request := httptest.NewRequest(http.MethodGet, "/tags/fun/20230330?"+tt.query, nil)
+			got, _, err := af.expandedArticles(request, "fun", 20230330, fields)
+			assert.NoError(t, err)
+			assert.Equal(t, responses.ProjectedTaggedArticles{
+				Tag:         "fun",
+				Count:       2,
+				Articles:    tt.want,
+				RelatedTags: []string{"health"},
+			}, got)
+		})
+	}
+}
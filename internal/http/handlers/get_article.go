@@ -3,22 +3,27 @@ package handlers
 import (
 	"article-dispatcher/internal/domain/adaptors/logger"
 	"article-dispatcher/internal/domain/services"
-	"strconv"
 
 	"github.com/gorilla/mux"
 	"github.com/prometheus/client_golang/prometheus"
 
-	"encoding/json"
 	"fmt"
 	"net/http"
+	"regexp"
 	"time"
 )
 
+// articleIDPattern - article ids are either generated integers or client chosen ids of
+// letters, digits, `.`, `_` and `-`
+var articleIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
 type ArticleGetHandler struct {
 	Log                  logger.Logger
 	ArticleService       services.ArticleService
 	ErrorHandler         ErrorHandler
 	RequestLatencyReport *prometheus.SummaryVec
+	// CacheControl value of the Cache-Control response header, empty omits the header
+	CacheControl string
 }
 
 func (ag ArticleGetHandler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
@@ -48,22 +53,23 @@ func (ag ArticleGetHandler) ServeHTTP(writer http.ResponseWriter, request *http.
 		return
 	}
 
-	r, err := json.Marshal(article)
+	r, contentType, err := encodeResponse(request.Context(), article)
 	if err != nil {
-		ag.ErrorHandler.Handle(request.Context(), writer,
-			ResponseMarshalError{fmt.Errorf("error marshaling response data, %w", err)})
+		ag.ErrorHandler.Handle(request.Context(), writer, err)
+		return
+	}
+	if writeConditional(writer, request, versionETag(article.Version, contentType), article.StoredAt, ag.CacheControl) {
+		return
 	}
-	writer.Header().Add("Content-Type", "application/json")
+	writer.Header().Add("Content-Type", contentType)
 	writer.WriteHeader(http.StatusOK)
 	_, err = writer.Write(r)
 	if err != nil {
-		ag.Log.Error(fmt.Sprintf("error writing to response due to, %s", err))
+		logger.FromContext(request.Context(), ag.Log).Error("error writing to response", logger.F("error", err))
 	}
 }
 
-// validateArticleID - converts the id path parameter into an integer and check for errors,
-// if it converts into an integer it validates as a valid article id
+// validateArticleID - validates the id path parameter against the article id pattern
 func validateArticleID(id string) bool {
-	_, err := strconv.Atoi(id)
-	return err == nil
+	return articleIDPattern.MatchString(id)
 }
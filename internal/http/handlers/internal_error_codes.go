@@ -1,8 +1,98 @@
 package handlers
 
+import (
+	"article-dispatcher/internal/http/responses"
+
+	"net/http"
+)
+
 const (
-	UnknownError            = 40000
-	InvalidRequestDataError = 40011
-	InvalidPayloadError     = 40012
-	InvalidRequestError     = 40013
+	UnknownError               = 40000
+	InvalidRequestDataError    = 40011
+	InvalidPayloadError        = 40012
+	InvalidRequestError        = 40013
+	DataConflictError          = 40014
+	PreconditionFailedError    = 40015
+	IdempotencyKeyReusedError  = 40016
+	IdempotencyInProgressError = 40017
+	VersionMismatchError       = 40018
+	UnsupportedEncodingError   = 40019
+	NotAcceptableError         = 40020
+	UnsupportedMediaTypeError  = 40021
+	UnauthenticatedError       = 40022
+	ForbiddenError             = 40023
+	InvalidTokenError          = 40024
+	ExpiredTokenError          = 40025
+	InvalidTokenClaimsError    = 40026
+	RateLimitedError           = 40027
+	PayloadTooLargeError       = 40028
+	UnknownFieldError          = 40029
+	TrailingDataError          = 40030
+	MissingContentTypeError    = 40031
+	NotFoundError              = 40032
+	UnavailableError           = 40033
+	InternalError              = 40034
 )
+
+// errorCatalogue internal error codes with their http status, published at `GET /errors`
+var errorCatalogue = []responses.ErrorCode{
+	{Code: UnknownError, Status: http.StatusInternalServerError, Title: "Unknown error",
+		Description: "unexpected error which is not mapped to any other code"},
+	{Code: InvalidRequestDataError, Status: http.StatusBadRequest, Title: "Invalid request data",
+		Description: "the data was rejected by the repository"},
+	{Code: InvalidPayloadError, Status: http.StatusBadRequest, Title: "Invalid payload",
+		Description: "the request body cannot be decoded"},
+	{Code: InvalidRequestError, Status: http.StatusBadRequest, Title: "Invalid request",
+		Description: "the request parameters or the body fields failed validation"},
+	{Code: DataConflictError, Status: http.StatusConflict, Title: "Data conflict",
+		Description: "the write conflicts with a stored article"},
+	{Code: PreconditionFailedError, Status: http.StatusPreconditionFailed, Title: "Precondition failed",
+		Description: "the article already exists for a create only write"},
+	{Code: IdempotencyKeyReusedError, Status: http.StatusConflict, Title: "Idempotency key reused",
+		Description: "the idempotency key was used with a different request"},
+	{Code: IdempotencyInProgressError, Status: http.StatusConflict, Title: "Idempotency key in progress",
+		Description: "the original request of the idempotency key is still in progress"},
+	{Code: VersionMismatchError, Status: http.StatusPreconditionFailed, Title: "Version mismatch",
+		Description: "the article is not at the expected version"},
+	{Code: UnsupportedEncodingError, Status: http.StatusUnsupportedMediaType, Title: "Unsupported content encoding",
+		Description: "the request content encoding is not supported"},
+	{Code: NotAcceptableError, Status: http.StatusNotAcceptable, Title: "Not acceptable",
+		Description: "the response cannot be represented in any of the accepted media types"},
+	{Code: UnsupportedMediaTypeError, Status: http.StatusUnsupportedMediaType, Title: "Unsupported media type",
+		Description: "the request content type is not supported"},
+	{Code: UnauthenticatedError, Status: http.StatusUnauthorized, Title: "Unauthenticated",
+		Description: "the request has no valid credentials"},
+	{Code: ForbiddenError, Status: http.StatusForbidden, Title: "Forbidden",
+		Description: "the credentials are missing the scope of the route"},
+	{Code: InvalidTokenError, Status: http.StatusUnauthorized, Title: "Invalid token",
+		Description: "the bearer token is malformed or not signed by a trusted key"},
+	{Code: ExpiredTokenError, Status: http.StatusUnauthorized, Title: "Expired token",
+		Description: "the bearer token is expired or not yet valid"},
+	{Code: InvalidTokenClaimsError, Status: http.StatusUnauthorized, Title: "Invalid token claims",
+		Description: "the bearer token has an unexpected issuer or audience"},
+	{Code: RateLimitedError, Status: http.StatusTooManyRequests, Title: "Rate limit exceeded",
+		Description: "the client exceeded the rate limit of the route"},
+	{Code: PayloadTooLargeError, Status: http.StatusRequestEntityTooLarge, Title: "Payload too large",
+		Description: "the request body exceeds the maximum body size"},
+	{Code: UnknownFieldError, Status: http.StatusBadRequest, Title: "Unknown field",
+		Description: "the request body has a field which is not known with strict decoding"},
+	{Code: TrailingDataError, Status: http.StatusBadRequest, Title: "Trailing data",
+		Description: "the request body has data after the json value with strict decoding"},
+	{Code: MissingContentTypeError, Status: http.StatusUnsupportedMediaType, Title: "Missing content type",
+		Description: "the request body has no content type with strict decoding"},
+	{Code: NotFoundError, Status: http.StatusNotFound, Title: "Not found",
+		Description: "the requested resource does not exist"},
+	{Code: UnavailableError, Status: http.StatusServiceUnavailable, Title: "Unavailable",
+		Description: "a dependency is temporarily unavailable, the request can be retried"},
+	{Code: InternalError, Status: http.StatusInternalServerError, Title: "Internal error",
+		Description: "unexpected failure of the service"},
+}
+
+// errorCodes catalogue entries by their code
+var errorCodes = func() map[int]responses.ErrorCode {
+	codes := make(map[int]responses.ErrorCode, len(errorCatalogue))
+	for _, c := range errorCatalogue {
+		codes[c.Code] = c
+	}
+	return codes
+}()
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"article-dispatcher/internal/domain/adaptors/logger"
+	"article-dispatcher/internal/domain/models"
+	"article-dispatcher/internal/domain/services"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+type ArticleListHandler struct {
+	Log                  logger.Logger
+	ArticleService       services.ArticleService
+	ErrorHandler         ErrorHandler
+	RequestLatencyReport *prometheus.SummaryVec
+	// DefaultLimit page size when the limit is not requested, MaxLimit maximum page size accepted,
+	// zero means unlimited for both
+	DefaultLimit int
+	MaxLimit     int
+}
+
+// ServeHTTP return a sorted page of the articles matching with the query parameters,
+// if errors occur it will be sent to the error handler
+func (al ArticleListHandler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	start := time.Now()
+	var err error
+	defer func() {
+		al.RequestLatencyReport.
+			With(map[string]string{"endpoint": "list_article", "error": fmt.Sprintf(`%t`, err != nil)}).
+			Observe(float64(time.Since(start).Microseconds()))
+	}()
+
+	// capture and validate query params
+	query, err := al.listQuery(request.URL.Query())
+	if err != nil {
+		al.ErrorHandler.Handle(request.Context(), writer, ValidationError{err})
+		return
+	}
+
+	page, err := al.ArticleService.List(request.Context(), query)
+	if err != nil {
+		err = fmt.Errorf("error listing articles data due to, %w", err)
+		al.ErrorHandler.Handle(request.Context(), writer, err)
+		return
+	}
+
+	r, contentType, err := encodeResponse(request.Context(), page)
+	if err != nil {
+		al.ErrorHandler.Handle(request.Context(), writer, err)
+		return
+	}
+	writer.Header().Add("Content-Type", contentType)
+	writer.WriteHeader(http.StatusOK)
+	_, err = writer.Write(r)
+	if err != nil {
+		logger.FromContext(request.Context(), al.Log).Error("error writing to response", logger.F("error", err))
+	}
+}
+
+// listQuery - build the repository query from the request query parameters
+func (al ArticleListHandler) listQuery(values url.Values) (models.Query, error) {
+	query := models.Query{
+		Tags:          splitQueryList(values[QueryParameterTag]),
+		DateFrom:      values.Get(QueryParameterDateFrom),
+		DateTo:        values.Get(QueryParameterDateTo),
+		TitleContains: values.Get(QueryParameterTitle),
+		SortBy:        models.SortByID,
+		Order:         models.SortAscending,
+		Limit:         al.DefaultLimit,
+		Cursor:        values.Get(QueryParameterCursor),
+	}
+
+	for _, date := range []string{query.DateFrom, query.DateTo} {
+		if date != "" && !validateArticleDate(date) {
+			return query, fmt.Errorf("invalid date format [%s] expected yyyy-mm-dd", date)
+		}
+	}
+	if query.DateFrom != "" && query.DateTo != "" && query.DateFrom > query.DateTo {
+		return query, fmt.Errorf("date from [%s] cannot be after date to [%s]", query.DateFrom, query.DateTo)
+	}
+
+	if sortBy := values.Get(QueryParameterSort); sortBy != "" {
+		switch models.SortField(sortBy) {
+		case models.SortByDate, models.SortByID, models.SortByTitle:
+			query.SortBy = models.SortField(sortBy)
+		default:
+			return query, fmt.Errorf("invalid sort field [%s]", sortBy)
+		}
+	}
+
+	if order := values.Get(QueryParameterOrder); order != "" {
+		switch models.SortOrder(order) {
+		case models.SortAscending, models.SortDescending:
+			query.Order = models.SortOrder(order)
+		default:
+			return query, fmt.Errorf("invalid sort order [%s]", order)
+		}
+	}
+
+	if limit := values.Get(QueryParameterLimit); limit != "" {
+		l, err := strconv.Atoi(limit)
+		if err != nil || l < 1 {
+			return query, fmt.Errorf("invalid limit [%s]", limit)
+		}
+		query.Limit = l
+	}
+	if al.MaxLimit > 0 && (query.Limit == 0 || query.Limit > al.MaxLimit) {
+		query.Limit = al.MaxLimit
+	}
+
+	return query, nil
+}
+
+// validateArticleDate - validate the date in the article date format `yyyy-mm-dd`
+func validateArticleDate(date string) bool {
+	_, err := time.Parse("2006-01-02", date)
+
+	return err == nil
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"article-dispatcher/internal/domain/adaptors/logger"
+	"article-dispatcher/internal/pkg/log"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// LogLevelHandler serves the runtime log levels on GET and replaces them on PUT. The body is decoded strictly,
+// so that a misspelt field cannot silently clear the overrides
+type LogLevelHandler struct {
+	Log                  logger.Logger
+	Levels               *log.Levels
+	ErrorHandler         ErrorHandler
+	RequestLatencyReport *prometheus.SummaryVec
+}
+
+func (lh LogLevelHandler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	start := time.Now()
+	var err error
+	defer func() {
+		lh.RequestLatencyReport.
+			With(map[string]string{"endpoint": "log_level", "error": fmt.Sprintf(`%t`, err != nil)}).
+			Observe(float64(time.Since(start).Microseconds()))
+	}()
+
+	if request.Method == http.MethodPut {
+		var levels log.LevelConfig
+		if err = decodeJSONBody(request, &levels, true); err != nil {
+			lh.ErrorHandler.Handle(request.Context(), writer, err)
+			return
+		}
+		if err = lh.Levels.Apply(levels); err != nil {
+			err = ValidationError{err}
+			lh.ErrorHandler.Handle(request.Context(), writer, err)
+			return
+		}
+		logger.FromContext(request.Context(), lh.Log).Info("log levels changed", logger.F("level", levels.Level),
+			logger.F("overrides", len(levels.Overrides)))
+	}
+
+	r, contentType, err := encodeResponse(request.Context(), lh.Levels.State())
+	if err != nil {
+		lh.ErrorHandler.Handle(request.Context(), writer, err)
+		return
+	}
+	writer.Header().Add("Content-Type", contentType)
+	writer.WriteHeader(http.StatusOK)
+	_, err = writer.Write(r)
+	if err != nil {
+		logger.FromContext(request.Context(), lh.Log).Error("error writing to response", logger.F("error", err))
+	}
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"article-dispatcher/internal/http/encoders"
+
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// NegotiationMiddleware negotiates the response encoders with the `Accept` header, errors are written as RFC 7807
+// problem details when `application/problem+json` is explicitly accepted. Problem details being json, clients accepting
+// only them get the successful responses as json
+type NegotiationMiddleware struct {
+	Registry     *encoders.Registry
+	ErrorHandler ErrorHandler
+}
+
+func (nm NegotiationMiddleware) MiddleFunc(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Add("Vary", "Accept")
+		accept := request.Header.Get("Accept")
+		problem := encoders.Accepts(accept, MediaTypeProblemJSON)
+		if problem {
+			request = request.WithContext(context.WithValue(request.Context(), ParamProblemDetails, true))
+		}
+		negotiated := nm.Registry.Negotiate(accept)
+		if len(negotiated) == 0 && problem {
+			negotiated = []encoders.Encoder{encoders.JSON{}}
+		}
+		if len(negotiated) == 0 {
+			nm.ErrorHandler.Handle(request.Context(), writer,
+				NotAcceptable{fmt.Errorf("none of the accepted media types [%s] are supported", accept)})
+			return
+		}
+
+		request = request.WithContext(context.WithValue(request.Context(), ParamEncoders, negotiated))
+		handler.ServeHTTP(writer, request)
+	})
+}
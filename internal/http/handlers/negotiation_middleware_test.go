@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"article-dispatcher/internal/http/encoders"
+
+	"github.com/stretchr/testify/assert"
+
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNegotiationMiddleware(t *testing.T) {
+	nm := NegotiationMiddleware{Registry: encoders.DefaultRegistry(), ErrorHandler: testErrorHandler(t)}
+	handler := nm.MiddleFunc(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		body, contentType, err := encodeResponse(request.Context(), map[string]string{"id": "1"})
+		if err != nil {
+			nm.ErrorHandler.Handle(request.Context(), writer, err)
+			return
+		}
+		writer.Header().Set("Content-Type", contentType)
+		_, _ = writer.Write(body)
+	}))
+
+	tests := []struct {
+		name            string
+		accept          string
+		wantStatus      int
+		wantContentType string
+	}{
+		{name: "missing_accept", wantStatus: http.StatusOK, wantContentType: "application/json"},
+		{name: "xml", accept: "application/xml", wantStatus: http.StatusOK, wantContentType: "application/xml"},
+		{name: "json_and_problem", accept: "application/json, application/problem+json", wantStatus: http.StatusOK,
+			wantContentType: "application/json"},
+		{name: "only_problem", accept: "application/problem+json", wantStatus: http.StatusOK,
+			wantContentType: "application/json"},
+		{name: "unsupported", accept: "image/png", wantStatus: http.StatusNotAcceptable,
+			wantContentType: "application/json"},
+		{name: "unsupported_with_problem", accept: "image/png, application/problem+json;q=0.5", wantStatus: http.StatusOK,
+			wantContentType: "application/json"},
+		{name: "problem_excluded", accept: "image/png, application/problem+json;q=0", wantStatus: http.StatusNotAcceptable,
+			wantContentType: "application/json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/articles/1", nil)
+			if tt.accept != "" {
+				request.Header.Set("Accept", tt.accept)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			assert.Equal(t, tt.wantStatus, recorder.Code)
+			assert.Equal(t, tt.wantContentType, recorder.Header().Get("Content-Type"))
+			assert.Equal(t, "Accept", recorder.Header().Get("Vary"))
+		})
+	}
+}
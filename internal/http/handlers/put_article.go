@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"article-dispatcher/internal/domain/adaptors/logger"
+	"article-dispatcher/internal/domain/models"
+	"article-dispatcher/internal/domain/services"
+	"article-dispatcher/internal/http/responses"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"fmt"
+	"net/http"
+	"time"
+)
+
+type ArticlePutHandler struct {
+	Log                  logger.Logger
+	ArticleService       services.ArticleService
+	ErrorHandler         ErrorHandler
+	RequestLatencyReport *prometheus.SummaryVec
+	// StrictDecoding rejects unknown fields, trailing data and bodies without a content type
+	StrictDecoding bool
+}
+
+// ServeHTTP create the article with the client chosen id or replace the existing one,
+// `If-None-Match: *` only allows creating the article while `If-Match` or the body version only allows replacing
+// the article at that version
+func (ap ArticlePutHandler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	start := time.Now()
+	var err error
+	defer func() {
+		ap.RequestLatencyReport.
+			With(map[string]string{"endpoint": "put_article", "error": fmt.Sprintf(`%t`, err != nil)}).
+			Observe(float64(time.Since(start).Microseconds()))
+	}()
+
+	// capture path params
+	articleID := mux.Vars(request)[PathParameterArticleID]
+
+	// validate input article id
+	if !validateArticleID(articleID) {
+		err = fmt.Errorf("invalid article id format")
+		ap.ErrorHandler.Handle(request.Context(), writer, ValidationError{err})
+		return
+	}
+
+	article, err := decodeArticle(request, ap.StrictDecoding)
+	if err != nil {
+		ap.ErrorHandler.Handle(request.Context(), writer, err)
+		return
+	}
+
+	// the body id is optional but cannot differ from the path id
+	if article.Id != "" && article.Id != articleID {
+		err = fmt.Errorf("article id [%s] does not match the path id [%s]", article.Id, articleID)
+		ap.ErrorHandler.Handle(request.Context(), writer, ValidationError{err})
+		return
+	}
+	article.Id = articleID
+
+	// validate request struct
+	if err = validate(&article); err != nil {
+		ap.ErrorHandler.Handle(request.Context(), writer, err)
+		return
+	}
+
+	precondition, err := putPrecondition(request, article.Version)
+	if err != nil {
+		ap.ErrorHandler.Handle(request.Context(), writer, err)
+		return
+	}
+
+	// call service to create or replace the article
+	created, err := ap.ArticleService.Put(request.Context(), &article, precondition)
+	if err != nil {
+		ap.ErrorHandler.Handle(request.Context(), writer, fmt.Errorf("error putting article with, %w", err))
+		return
+	}
+
+	resp := responses.SuccessResponse{}
+	resp.Data.ID = article.Id
+	r, contentType, err := encodeResponse(request.Context(), resp)
+	if err != nil {
+		ap.ErrorHandler.Handle(request.Context(), writer, err)
+		return
+	}
+
+	writer.Header().Set(HeaderETag, versionETag(article.Version, contentType))
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+		writer.Header().Add("Location", fmt.Sprintf("/articles/%s", article.Id))
+	}
+	writer.Header().Add("Content-Type", contentType)
+	writer.WriteHeader(status)
+	_, err = writer.Write(r)
+	if err != nil {
+		logger.FromContext(request.Context(), ap.Log).Error("error writing to response", logger.F("error", err))
+	}
+}
+
+// putPrecondition - build the write precondition from the If-None-Match and If-Match headers, the body version
+// is only used when If-Match is not present
+func putPrecondition(request *http.Request, bodyVersion int64) (models.Precondition, error) {
+	precondition := models.Precondition{
+		CreateOnly: request.Header.Get(HeaderIfNoneMatch) == "*",
+	}
+
+	ifMatch := request.Header.Get(HeaderIfMatch)
+	if ifMatch == "" {
+		if bodyVersion != 0 {
+			precondition.MatchVersions = []int64{bodyVersion}
+		}
+		return precondition, nil
+	}
+
+	mustExist, versions, err := parseIfMatch(ifMatch)
+	if err != nil {
+		return precondition, VersionMismatch{err}
+	}
+	precondition.MustExist = mustExist
+	precondition.MatchVersions = versions
+	return precondition, nil
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"article-dispatcher/internal/http/auth"
+	"article-dispatcher/internal/http/ratelimit"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+)
+
+// RateLimitMiddleware limits the requests of each client per route, clients are identified by their authenticated
+// identity or else by their ip. Routes without a configured limit use the `Default` limit
+type RateLimitMiddleware struct {
+	Limiter      *ratelimit.Limiter
+	Default      ratelimit.Limit
+	RouteLimits  map[string]ratelimit.Limit
+	Rejections   *prometheus.CounterVec
+	ErrorHandler ErrorHandler
+	// ByIP limits each ip with the `Default` limit across all of the routes, to run before the authentication
+	ByIP bool
+}
+
+func (rm RateLimitMiddleware) MiddleFunc(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		route := ""
+		if r := mux.CurrentRoute(request); r != nil {
+			route = r.GetName()
+		}
+		limit, ok := rm.RouteLimits[route]
+		if !ok {
+			limit = rm.Default
+		}
+
+		client := clientKey(request)
+		key := route + "|" + client
+		if rm.ByIP {
+			client = ipKey(request)
+			key = client
+		}
+		decision := rm.Limiter.Allow(key, limit)
+		writer.Header().Set(HeaderRateLimitLimit, strconv.Itoa(decision.Limit))
+		writer.Header().Set(HeaderRateLimitRemaining, strconv.Itoa(decision.Remaining))
+		writer.Header().Set(HeaderRateLimitReset, strconv.Itoa(int(decision.Reset.Seconds())))
+		if !decision.Allowed {
+			if rm.Rejections != nil {
+				rm.Rejections.WithLabelValues(route).Inc()
+			}
+			writer.Header().Set(HeaderRetryAfter, strconv.Itoa(int(decision.RetryAfter.Seconds())))
+			rm.ErrorHandler.Handle(request.Context(), writer, RateLimited{
+				fmt.Errorf("rate limit of %d requests per %s exceeded for [%s]", limit.Requests, limit.Period, client)})
+			return
+		}
+		handler.ServeHTTP(writer, request)
+	})
+}
+
+// clientKey authenticated identity of the request, or else the ip of the client
+func clientKey(request *http.Request) string {
+	if identity, ok := request.Context().Value(ParamIdentity).(auth.Identity); ok {
+		return "identity:" + identity.ID
+	}
+	return ipKey(request)
+}
+
+// ipKey ip of the client
+func ipKey(request *http.Request) string {
+	host, _, err := net.SplitHostPort(request.RemoteAddr)
+	if err != nil {
+		host = request.RemoteAddr
+	}
+	return "ip:" + host
+}
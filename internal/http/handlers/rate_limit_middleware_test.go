@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"article-dispatcher/internal/http/auth"
+	"article-dispatcher/internal/http/ratelimit"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// rateLimitedRouter router of a single route limited by the middlewares
+func rateLimitedRouter(middlewares ...mux.MiddlewareFunc) *mux.Router {
+	router := mux.NewRouter()
+	router.Use(middlewares...)
+	router.HandleFunc("/articles/{id}", func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusOK)
+	}).Name("get_article")
+	return router
+}
+
+// limitedRequest request of the ip, authenticated as the identity when set
+func limitedRequest(ip, identity string) *http.Request {
+	request := httptest.NewRequest(http.MethodGet, "/articles/1", nil)
+	request.RemoteAddr = ip + ":52000"
+	if identity != "" {
+		request = request.WithContext(context.WithValue(request.Context(), ParamIdentity, auth.Identity{ID: identity}))
+	}
+	return request
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	rm := RateLimitMiddleware{
+		Limiter:      ratelimit.NewLimiter(time.Minute),
+		Default:      ratelimit.Limit{Requests: 10, Period: time.Minute},
+		RouteLimits:  map[string]ratelimit.Limit{"get_article": {Requests: 2, Period: time.Minute}},
+		ErrorHandler: testErrorHandler(t),
+	}
+	router := rateLimitedRouter(rm.MiddleFunc)
+
+	writer := httptest.NewRecorder()
+	router.ServeHTTP(writer, limitedRequest("10.0.0.1", "alice"))
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.Equal(t, "2", writer.Header().Get(HeaderRateLimitLimit))
+	assert.Equal(t, "1", writer.Header().Get(HeaderRateLimitRemaining))
+	assert.Equal(t, "30", writer.Header().Get(HeaderRateLimitReset))
+	assert.Empty(t, writer.Header().Get(HeaderRetryAfter))
+
+	writer = httptest.NewRecorder()
+	router.ServeHTTP(writer, limitedRequest("10.0.0.1", "alice"))
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.Equal(t, "0", writer.Header().Get(HeaderRateLimitRemaining))
+
+	writer = httptest.NewRecorder()
+	router.ServeHTTP(writer, limitedRequest("10.0.0.1", "alice"))
+	assert.Equal(t, http.StatusTooManyRequests, writer.Code)
+	assert.Equal(t, "2", writer.Header().Get(HeaderRateLimitLimit))
+	assert.Equal(t, "0", writer.Header().Get(HeaderRateLimitRemaining))
+	assert.Equal(t, "60", writer.Header().Get(HeaderRateLimitReset))
+	assert.Equal(t, "30", writer.Header().Get(HeaderRetryAfter))
+	assert.Contains(t, writer.Body.String(), "40027")
+
+	// other clients on the same ip have their own bucket
+	writer = httptest.NewRecorder()
+	router.ServeHTTP(writer, limitedRequest("10.0.0.1", "bob"))
+	assert.Equal(t, http.StatusOK, writer.Code)
+}
+
+func TestRateLimitMiddleware_ByIP(t *testing.T) {
+	limiter := ratelimit.NewLimiter(time.Minute)
+	ipLimit := RateLimitMiddleware{
+		Limiter:      limiter,
+		Default:      ratelimit.Limit{Requests: 2, Period: time.Minute},
+		ErrorHandler: testErrorHandler(t),
+		ByIP:         true,
+	}
+	// authentication rejecting every request
+	rejectAll := func(handler http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			writer.WriteHeader(http.StatusUnauthorized)
+		})
+	}
+	router := rateLimitedRouter(ipLimit.MiddleFunc, rejectAll)
+
+	// unauthenticated requests are limited before the authentication
+	for i := 0; i < 2; i++ {
+		writer := httptest.NewRecorder()
+		router.ServeHTTP(writer, limitedRequest("10.0.0.2", ""))
+		assert.Equal(t, http.StatusUnauthorized, writer.Code)
+	}
+	writer := httptest.NewRecorder()
+	router.ServeHTTP(writer, limitedRequest("10.0.0.2", ""))
+	assert.Equal(t, http.StatusTooManyRequests, writer.Code)
+	assert.Equal(t, "30", writer.Header().Get(HeaderRetryAfter))
+
+	// other ips are not limited
+	writer = httptest.NewRecorder()
+	router.ServeHTTP(writer, limitedRequest("10.0.0.3", ""))
+	assert.Equal(t, http.StatusUnauthorized, writer.Code)
+}
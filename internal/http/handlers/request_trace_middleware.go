@@ -2,23 +2,102 @@ package handlers
 
 import (
 	"article-dispatcher/internal/domain/adaptors/logger"
+	"article-dispatcher/internal/domain/adaptors/tracer"
+	"article-dispatcher/internal/pkg/tracing"
 
 	"github.com/google/uuid"
+	"github.com/gorilla/mux"
 
 	"context"
 	"fmt"
 	"net/http"
 )
 
+// Middleware attaches the request id and W3C trace context to the request and echoes them in the response headers.
+// A valid inbound X-Request-ID is kept, otherwise the trace id of a valid traceparent is used, and only when both are
+// missing a new id is generated. The logger of the request context annotates its entries with the W3C trace id, the
+// request id, the method and the route. The request is timed in the root span of the trace when a tracer is set.
 type Middleware struct {
 	Logger logger.Logger
+	Tracer tracer.Tracer
 }
 
+// attribute keys of the request spans
+const (
+	attributeHTTPMethod    = "http.method"
+	attributeHTTPRoute     = "http.route"
+	attributeHTTPStatus    = "http.status_code"
+	attributeHTTPRequestID = "http.request_id"
+)
+
 func (mw Middleware) MiddleFunc(handler http.Handler) http.Handler {
 	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
-		traceID := uuid.New()
-		request = request.WithContext(context.WithValue(request.Context(), ParamTraceID, traceID))
-		mw.Logger.Debug(fmt.Sprintf("request received with trace-id:[%s] url [%s]", traceID, request.URL))
-		handler.ServeHTTP(writer, request)
+		tc, err := tracing.ParseTraceParent(request.Header.Get(tracing.HeaderTraceParent))
+		if err != nil {
+			if request.Header.Get(tracing.HeaderTraceParent) != "" {
+				mw.Logger.Debug("discarding invalid traceparent",
+					logger.F("traceparent", request.Header.Get(tracing.HeaderTraceParent)), logger.F("error", err))
+			}
+			tc = tracing.TraceContext{TraceID: tracing.NewTraceID(), Flags: "00"}
+		} else if state := request.Header.Get(tracing.HeaderTraceState); tracing.ValidTraceState(state) {
+			tc.State = state
+		}
+		tc.SpanID = tracing.NewSpanID()
+
+		requestID := request.Header.Get(tracing.HeaderRequestID)
+		if !tracing.ValidRequestID(requestID) {
+			if requestID != "" {
+				mw.Logger.Debug("discarding invalid request id", logger.F("request_id", requestID))
+			}
+			requestID = uuid.New().String()
+			if tc.ParentID != "" {
+				requestID = tc.TraceID
+			}
+		}
+
+		writer.Header().Set(tracing.HeaderRequestID, requestID)
+		writer.Header().Set(tracing.HeaderTraceParent, tc.TraceParent())
+		if tc.State != "" {
+			writer.Header().Set(tracing.HeaderTraceState, tc.State)
+		}
+
+		route := request.URL.Path
+		if current := mux.CurrentRoute(request); current != nil {
+			if template, err := current.GetPathTemplate(); err == nil {
+				route = template
+			}
+		}
+
+		// entries logged with the logger of the request are annotated with the request
+		requestLog := mw.Logger.With(logger.F("trace_id", tc.TraceID), logger.F("request_id", requestID),
+			logger.F("method", request.Method), logger.F("route", route))
+		ctx := tracing.WithTraceContext(tracing.WithRequestID(request.Context(), requestID), tc)
+		ctx = logger.NewContext(ctx, requestLog)
+		request = request.WithContext(context.WithValue(ctx, ParamRequestPath, request.URL.Path))
+		requestLog.Debug("request received", logger.F("traceparent", tc.TraceParent()),
+			logger.F("url", request.URL.String()))
+		if mw.Tracer == nil {
+			handler.ServeHTTP(writer, request)
+			return
+		}
+
+		ctx, span := mw.Tracer.Start(request.Context(), fmt.Sprintf("%s %s", request.Method, route))
+		defer span.End()
+		span.SetAttribute(attributeHTTPMethod, request.Method)
+		span.SetAttribute(attributeHTTPRoute, route)
+		span.SetAttribute(attributeHTTPRequestID, requestID)
+
+		recorder := newStatusRecorder(writer)
+		handler.ServeHTTP(recorder, request.WithContext(ctx))
+		span.SetAttribute(attributeHTTPStatus, recorder.status)
+		if recorder.status >= http.StatusInternalServerError {
+			span.RecordError(fmt.Errorf("request failed with status [%d]", recorder.status))
+		}
 	})
 }
+
+// requestID request id of the context, empty when the request did not pass the trace middleware
+func requestID(ctx context.Context) string {
+	id, _ := tracing.RequestID(ctx)
+	return id
+}
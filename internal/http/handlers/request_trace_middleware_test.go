@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"article-dispatcher/internal/adaptors/cache"
+	"article-dispatcher/internal/domain/adaptors/logger"
+	"article-dispatcher/internal/http/auth"
+	"article-dispatcher/internal/pkg/tracing"
+	"article-dispatcher/internal/services"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// capturedEntry entry of the capturing logger with the fields of the logger and of the entry
+type capturedEntry struct {
+	message string
+	fields  map[string]interface{}
+}
+
+// capturingLogger logger capturing its entries and the ones of its child loggers
+type capturingLogger struct {
+	lock    *sync.Mutex
+	entries *[]capturedEntry
+	fields  []logger.Field
+}
+
+func newCapturingLogger() capturingLogger {
+	return capturingLogger{lock: &sync.Mutex{}, entries: &[]capturedEntry{}}
+}
+
+func (l capturingLogger) capture(message string, fields ...logger.Field) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	entry := capturedEntry{message: message, fields: make(map[string]interface{})}
+	for _, f := range append(append([]logger.Field{}, l.fields...), fields...) {
+		entry.fields[f.Key] = f.Value
+	}
+	*l.entries = append(*l.entries, entry)
+}
+
+// entry captured entry of the message
+func (l capturingLogger) entry(message string) (capturedEntry, bool) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	for _, e := range *l.entries {
+		if e.message == message {
+			return e, true
+		}
+	}
+	return capturedEntry{}, false
+}
+
+func (l capturingLogger) Fatal(message string, fields ...logger.Field) { l.capture(message, fields...) }
+func (l capturingLogger) Error(message string, fields ...logger.Field) { l.capture(message, fields...) }
+func (l capturingLogger) Warn(message string, fields ...logger.Field)  { l.capture(message, fields...) }
+func (l capturingLogger) Debug(message string, fields ...logger.Field) { l.capture(message, fields...) }
+func (l capturingLogger) Info(message string, fields ...logger.Field)  { l.capture(message, fields...) }
+func (l capturingLogger) Trace(message string, fields ...logger.Field) { l.capture(message, fields...) }
+
+func (l capturingLogger) With(fields ...logger.Field) logger.Logger {
+	child := l
+	child.fields = append(append([]logger.Field{}, l.fields...), fields...)
+	return child
+}
+
+// identityAuthenticator authenticator of a fixed identity
+type identityAuthenticator struct {
+	identity auth.Identity
+}
+
+func (a identityAuthenticator) Authenticate(*http.Request) (auth.Identity, error) {
+	return a.identity, nil
+}
+
+func (a identityAuthenticator) Challenge() string {
+	return "ApiKey"
+}
+
+func TestMiddleware_requestLog(t *testing.T) {
+	l := newCapturingLogger()
+	errorHandler := ErrorHandler{Log: l}
+	service := services.NewArticleService(l, cache.NewCache(l), tracing.NoopTracer{})
+
+	router := mux.NewRouter()
+	router.Use(Middleware{Logger: l}.MiddleFunc)
+	router.Use(AuthMiddleware{
+		Log:            l,
+		Authenticators: []auth.Authenticator{identityAuthenticator{identity: auth.Identity{ID: "reader", Scopes: []auth.Scope{auth.ScopeRead}}}},
+		RouteScopes:    map[string]auth.Scope{"get_article": auth.ScopeRead},
+		ErrorHandler:   errorHandler,
+	}.MiddleFunc)
+	router.Handle("/articles/{id}", http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if _, err := service.Get(request.Context(), mux.Vars(request)[PathParameterArticleID]); err != nil {
+			errorHandler.Handle(request.Context(), writer, err)
+		}
+	})).Methods(http.MethodGet).Name("get_article")
+
+	request := httptest.NewRequest(http.MethodGet, "/articles/999", nil)
+	request.Header.Set(tracing.HeaderTraceParent, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	request.Header.Set(tracing.HeaderRequestID, "req-1")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+	want := map[string]interface{}{
+		"trace_id":   "4bf92f3577b34da6a3ce929d0e0e4736",
+		"request_id": "req-1",
+		"method":     http.MethodGet,
+		"route":      "/articles/{id}",
+		"client":     "reader",
+	}
+	// the entries of the auth middleware, the service and the error handler of the handler
+	for _, message := range []string{"request authenticated", "article service, get article error", "error executing request"} {
+		entry, found := l.entry(message)
+		if !assert.True(t, found, message) {
+			continue
+		}
+		for key, value := range want {
+			assert.Equal(t, value, entry.fields[key], "%s of [%s]", key, message)
+		}
+	}
+
+	// the request is received before being authenticated
+	entry, found := l.entry("request received")
+	if assert.True(t, found) {
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", entry.fields["trace_id"])
+		assert.Equal(t, "req-1", entry.fields["request_id"])
+		assert.NotContains(t, entry.fields, "client")
+	}
+}
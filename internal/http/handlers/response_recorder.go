@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"article-dispatcher/internal/http/idempotency"
+
+	"bytes"
+	"net/http"
+)
+
+// responseRecorder - buffers the response written by a handler so that it can be stored before sending
+type responseRecorder struct {
+	header     http.Header
+	statusCode int
+	body       *bytes.Buffer
+}
+
+func newResponseRecorder() *responseRecorder {
+	return &responseRecorder{
+		header:     make(http.Header),
+		statusCode: http.StatusOK,
+		body:       &bytes.Buffer{},
+	}
+}
+
+func (rr *responseRecorder) Header() http.Header {
+	return rr.header
+}
+
+func (rr *responseRecorder) Write(b []byte) (int, error) {
+	return rr.body.Write(b)
+}
+
+func (rr *responseRecorder) WriteHeader(statusCode int) {
+	rr.statusCode = statusCode
+}
+
+// response - the recorded response
+func (rr *responseRecorder) response() idempotency.Response {
+	return idempotency.Response{
+		StatusCode: rr.statusCode,
+		Header:     rr.header.Clone(),
+		Body:       rr.body.Bytes(),
+	}
+}
+
+// writeResponse - write the recorded or replayed response into the response writer
+func writeResponse(writer http.ResponseWriter, response idempotency.Response) error {
+	for key, values := range response.Header {
+		for _, value := range values {
+			writer.Header().Add(key, value)
+		}
+	}
+	writer.WriteHeader(response.StatusCode)
+	_, err := writer.Write(response.Body)
+	return err
+}
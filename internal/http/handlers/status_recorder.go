@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"net/http"
+)
+
+// statusRecorder response writer recording the status and the size of the response body as it is written
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int64
+	wroteHeader bool
+}
+
+func newStatusRecorder(writer http.ResponseWriter) *statusRecorder {
+	return &statusRecorder{ResponseWriter: writer, status: http.StatusOK}
+}
+
+func (sr *statusRecorder) WriteHeader(status int) {
+	if !sr.wroteHeader {
+		sr.status, sr.wroteHeader = status, true
+	}
+	sr.ResponseWriter.WriteHeader(status)
+}
+
+func (sr *statusRecorder) Write(b []byte) (int, error) {
+	sr.wroteHeader = true
+	n, err := sr.ResponseWriter.Write(b)
+	sr.bytes += int64(n)
+	return n, err
+}
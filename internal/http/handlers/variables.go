@@ -1,11 +1,46 @@
 package handlers
 
 const (
-	ParamTraceID ContextType = "trace-id"
+	ParamEncoders ContextType = "encoders"
+	ParamIdentity ContextType = "identity"
+	ParamClaims   ContextType = "claims"
+	// ParamProblemDetails errors are written as problem details
+	ParamProblemDetails ContextType = "problem-details"
+	ParamRequestPath    ContextType = "request-path"
+
+	MediaTypeProblemJSON = "application/problem+json"
 
 	PathParameterArticleID = "id"
 	PathParameterTag       = "tagName"
 	PathParameterDate      = "date"
+	PathParameterErrorCode = "code"
+
+	QueryParameterIDs      = "ids"
+	QueryParameterExpand   = "expand"
+	QueryParameterFields   = "fields"
+	QueryParameterTag      = "tag"
+	QueryParameterDateFrom = "from"
+	QueryParameterDateTo   = "to"
+	QueryParameterTitle    = "title"
+	QueryParameterSort     = "sort"
+	QueryParameterOrder    = "order"
+	QueryParameterLimit    = "limit"
+	QueryParameterCursor   = "cursor"
+
+	ExpandArticles = "articles"
+
+	HeaderIfNoneMatch        = "If-None-Match"
+	HeaderIfMatch            = "If-Match"
+	HeaderIfModifiedSince    = "If-Modified-Since"
+	HeaderETag               = "ETag"
+	HeaderLastModified       = "Last-Modified"
+	HeaderCacheControl       = "Cache-Control"
+	HeaderIdempotencyKey     = "Idempotency-Key"
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+	HeaderRateLimitLimit     = "RateLimit-Limit"
+	HeaderRateLimitRemaining = "RateLimit-Remaining"
+	HeaderRateLimitReset     = "RateLimit-Reset"
+	HeaderRetryAfter         = "Retry-After"
 )
 
 type ContextType string
@@ -0,0 +1,110 @@
+package idempotency
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrKeyReused key was already used with a request of a different fingerprint
+	ErrKeyReused = errors.New("idempotency key reused with a different request")
+	// ErrInProgress original request of the key is still being processed
+	ErrInProgress = errors.New("idempotency key request in progress")
+)
+
+// Response recorded response of the original request to be replayed for the duplicates
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// Store idempotency keys with the fingerprint of the request and its recorded response
+// Begin - reserve the key for the request, returns the recorded response if the key was already completed
+// Complete - record the response of the reserved key
+// Release - drop the reserved key so that the request can be retried
+type Store interface {
+	Begin(key, fingerprint string) (*Response, error)
+	Complete(key string, response Response)
+	Release(key string)
+}
+
+type entry struct {
+	fingerprint string
+	response    *Response
+	expiresAt   time.Time
+}
+
+type memoryStore struct {
+	lock      *sync.Mutex
+	ttl       time.Duration
+	entries   map[string]*entry
+	nextSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryStore create an in-memory store keeping the keys for the ttl after they were reserved
+func NewMemoryStore(ttl time.Duration) Store {
+	return &memoryStore{
+		lock:    &sync.Mutex{},
+		ttl:     ttl,
+		entries: make(map[string]*entry),
+		now:     time.Now,
+	}
+}
+
+// Begin reserve the key, or return the recorded response when the key was completed with the same fingerprint
+func (s *memoryStore) Begin(key, fingerprint string) (*Response, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	now := s.now()
+	s.sweep(now)
+
+	e, ok := s.entries[key]
+	if !ok || now.After(e.expiresAt) {
+		s.entries[key] = &entry{
+			fingerprint: fingerprint,
+			expiresAt:   now.Add(s.ttl),
+		}
+		return nil, nil
+	}
+
+	if e.fingerprint != fingerprint {
+		return nil, ErrKeyReused
+	}
+	if e.response == nil {
+		return nil, ErrInProgress
+	}
+	return e.response, nil
+}
+
+// Complete record the response of the reserved key
+func (s *memoryStore) Complete(key string, response Response) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if e, ok := s.entries[key]; ok {
+		e.response = &response
+	}
+}
+
+// Release drop the reserved key
+func (s *memoryStore) Release(key string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.entries, key)
+}
+
+// sweep - remove the expired keys at most once per ttl, caller must hold the lock
+func (s *memoryStore) sweep(now time.Time) {
+	if now.Before(s.nextSweep) {
+		return
+	}
+	for key, e := range s.entries {
+		if now.After(e.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+	s.nextSweep = now.Add(s.ttl)
+}
@@ -0,0 +1,52 @@
+package idempotency
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore(t *testing.T) {
+	now := time.Date(2023, 3, 30, 0, 0, 0, 0, time.UTC)
+	s := &memoryStore{
+		lock:    &sync.Mutex{},
+		ttl:     time.Hour,
+		entries: make(map[string]*entry),
+		now:     func() time.Time { return now },
+	}
+	response := Response{StatusCode: http.StatusCreated, Body: []byte(`{"data":{"id":"1"}}`)}
+
+	// first request reserves the key
+	got, err := s.Begin("key", "fingerprint")
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+
+	// duplicate while the first request is processed
+	_, err = s.Begin("key", "fingerprint")
+	assert.Equal(t, ErrInProgress, err)
+
+	// duplicate after completion replays the response
+	s.Complete("key", response)
+	got, err = s.Begin("key", "fingerprint")
+	assert.NoError(t, err)
+	assert.Equal(t, &response, got)
+
+	// same key with a different request
+	_, err = s.Begin("key", "other")
+	assert.Equal(t, ErrKeyReused, err)
+
+	// expired key is reserved again
+	now = now.Add(2 * time.Hour)
+	got, err = s.Begin("key", "other")
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+
+	// released key can be retried
+	s.Release("key")
+	got, err = s.Begin("key", "fingerprint")
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+}
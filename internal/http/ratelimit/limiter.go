@@ -0,0 +1,138 @@
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Limit bucket of `Requests` tokens refilled evenly over the `Period`
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// ParseLimit parse a limit in the `<requests>/<period>` format, e.g. `60/1m`
+func ParseLimit(s string) (Limit, error) {
+	parts := strings.SplitN(s, "/", 2)
+	if len(parts) != 2 {
+		return Limit{}, fmt.Errorf("rate limit [%s] must be in the <requests>/<period> format", s)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil || n <= 0 {
+		return Limit{}, fmt.Errorf("rate limit [%s] requests must be a positive number", s)
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(parts[1]))
+	if err != nil || d <= 0 {
+		return Limit{}, fmt.Errorf("rate limit [%s] period must be a positive duration", s)
+	}
+	return Limit{Requests: n, Period: d}, nil
+}
+
+// ParseRouteLimits parse the limits of the routes in the `<route>=<requests>/<period>` format
+func ParseRouteLimits(entries []string) (map[string]Limit, error) {
+	limits := make(map[string]Limit, len(entries))
+	for _, entry := range entries {
+		parts := strings.SplitN(strings.TrimSpace(entry), "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("route rate limit [%s] must be in the <route>=<requests>/<period> format", entry)
+		}
+		route := parts[0]
+		limit, err := ParseLimit(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate limit of route [%s], %w", route, err)
+		}
+		limits[route] = limit
+	}
+	return limits, nil
+}
+
+// rate tokens refilled per second
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Period.Seconds()
+}
+
+// Decision result of taking a token from the bucket
+// Remaining - whole tokens left in the bucket
+// Reset - time until the bucket is full again
+// RetryAfter - time until the next token when the request was not allowed
+type Decision struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// Limiter token buckets of the clients
+type Limiter struct {
+	lock      *sync.Mutex
+	buckets   map[string]*bucket
+	nextSweep time.Time
+	maxPeriod time.Duration
+	now       func() time.Time
+}
+
+// NewLimiter create an in-memory limiter, full buckets idle for longer than the max period are dropped
+func NewLimiter(maxPeriod time.Duration) *Limiter {
+	return &Limiter{
+		lock:      &sync.Mutex{},
+		buckets:   make(map[string]*bucket),
+		maxPeriod: maxPeriod,
+		now:       time.Now,
+	}
+}
+
+// Allow take a token from the bucket of the key
+func (l *Limiter) Allow(key string, limit Limit) Decision {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	now := l.now()
+	l.sweep(now)
+
+	capacity := float64(limit.Requests)
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, updated: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(capacity, b.tokens+now.Sub(b.updated).Seconds()*limit.rate())
+	b.updated = now
+
+	decision := Decision{Limit: limit.Requests}
+	if b.tokens >= 1 {
+		b.tokens--
+		decision.Allowed = true
+	} else {
+		decision.RetryAfter = seconds((1 - b.tokens) / limit.rate())
+	}
+	decision.Remaining = int(b.tokens)
+	decision.Reset = seconds((capacity - b.tokens) / limit.rate())
+	return decision
+}
+
+// sweep drop the idle buckets, at most once per max period
+func (l *Limiter) sweep(now time.Time) {
+	if now.Before(l.nextSweep) {
+		return
+	}
+	for key, b := range l.buckets {
+		if now.Sub(b.updated) > l.maxPeriod {
+			delete(l.buckets, key)
+		}
+	}
+	l.nextSweep = now.Add(l.maxPeriod)
+}
+
+// seconds duration of the seconds rounded up to the second
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Ceil(s)) * time.Second
+}
@@ -0,0 +1,62 @@
+package ratelimit
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	"testing"
+	"time"
+)
+
+func TestParseLimit(t *testing.T) {
+	limit, err := ParseLimit("60/1m")
+	assert.NoError(t, err)
+	assert.Equal(t, Limit{Requests: 60, Period: time.Minute}, limit)
+
+	for _, invalid := range []string{"", "60", "0/1m", "60/0s", "x/1m", "60/x"} {
+		_, err = ParseLimit(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestParseRouteLimits(t *testing.T) {
+	limits, err := ParseRouteLimits([]string{"create_article=60/1m", " get_article=10/1s"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]Limit{
+		"create_article": {Requests: 60, Period: time.Minute},
+		"get_article":    {Requests: 10, Period: time.Second},
+	}, limits)
+
+	_, err = ParseRouteLimits([]string{"create_article"})
+	assert.Error(t, err)
+	_, err = ParseRouteLimits([]string{"create_article=60"})
+	assert.Error(t, err)
+}
+
+func TestLimiter(t *testing.T) {
+	now := time.Date(2023, 3, 30, 0, 0, 0, 0, time.UTC)
+	l := NewLimiter(time.Minute)
+	l.now = func() time.Time { return now }
+	limit := Limit{Requests: 2, Period: 10 * time.Second}
+
+	d := l.Allow("client", limit)
+	assert.Equal(t, Decision{Allowed: true, Limit: 2, Remaining: 1, Reset: 5 * time.Second}, d)
+	d = l.Allow("client", limit)
+	assert.Equal(t, Decision{Allowed: true, Limit: 2, Remaining: 0, Reset: 10 * time.Second}, d)
+
+	// empty bucket is rejected until a token is refilled
+	d = l.Allow("client", limit)
+	assert.Equal(t, Decision{Allowed: false, Limit: 2, Remaining: 0, Reset: 10 * time.Second,
+		RetryAfter: 5 * time.Second}, d)
+
+	// other clients have their own bucket
+	assert.True(t, l.Allow("other", limit).Allowed)
+
+	now = now.Add(5 * time.Second)
+	assert.True(t, l.Allow("client", limit).Allowed)
+	assert.False(t, l.Allow("client", limit).Allowed)
+
+	// idle buckets are swept
+	now = now.Add(2 * time.Minute)
+	l.Allow("client", limit)
+	assert.Len(t, l.buckets, 1)
+}
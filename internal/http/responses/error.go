@@ -1,8 +1,24 @@
 package responses
 
 type ErrorResponse struct {
-	StatusCode  int    `json:"-"`
+	StatusCode  int          `json:"-"`
+	Code        int          `json:"code"`
+	Description string       `json:"description"`
+	Trace       string       `json:"trace"`
+	Errors      []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError failed validation rule of a request field
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// ErrorCode internal error code of the error catalogue
+type ErrorCode struct {
 	Code        int    `json:"code"`
+	Status      int    `json:"status"`
+	Title       string `json:"title"`
 	Description string `json:"description"`
-	Trace       string `json:"trace"`
 }
@@ -0,0 +1,14 @@
+package responses
+
+// ProblemDetails RFC 7807 error response, the internal error code, the trace and the failed fields are extension
+// members
+type ProblemDetails struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Code     int          `json:"code"`
+	Trace    string       `json:"trace"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
@@ -0,0 +1,9 @@
+package responses
+
+// ProjectedTaggedArticles tagged articles embedding only the selected fields of each article
+type ProjectedTaggedArticles struct {
+	Tag         string                   `json:"tag"`
+	Count       int                      `json:"count"`
+	Articles    []map[string]interface{} `json:"articles"`
+	RelatedTags []string                 `json:"related_tags"`
+}
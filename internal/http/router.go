@@ -2,8 +2,14 @@ package http
 
 import (
 	"article-dispatcher/internal/domain/adaptors/logger"
+	"article-dispatcher/internal/domain/adaptors/tracer"
 	"article-dispatcher/internal/domain/services"
+	"article-dispatcher/internal/http/auth"
+	"article-dispatcher/internal/http/encoders"
 	"article-dispatcher/internal/http/handlers"
+	"article-dispatcher/internal/http/idempotency"
+	"article-dispatcher/internal/http/ratelimit"
+	"article-dispatcher/internal/pkg/log"
 
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
@@ -12,29 +18,117 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"os"
 )
 
+// route names used to authorize the scope of the requests
+const (
+	routeCreateArticle  = "create_article"
+	routeBatchArticles  = "batch_articles"
+	routeBatchPost      = "batch_articles_post"
+	routeListArticles   = "list_articles"
+	routeGetArticle     = "get_article"
+	routePutArticle     = "put_article"
+	routeFilterArticles = "filter_articles"
+	routeErrorCatalogue = "error_catalogue"
+	routeErrorCode      = "error_code"
+	routeLogLevel       = "log_level"
+	routePutLogLevel    = "put_log_level"
+)
+
+// routeScopes scope required by each route
+var routeScopes = map[string]auth.Scope{
+	routeCreateArticle:  auth.ScopeWrite,
+	routeBatchArticles:  auth.ScopeRead,
+	routeBatchPost:      auth.ScopeRead,
+	routeListArticles:   auth.ScopeRead,
+	routeGetArticle:     auth.ScopeRead,
+	routePutArticle:     auth.ScopeWrite,
+	routeFilterArticles: auth.ScopeRead,
+	routeErrorCatalogue: auth.ScopePublic,
+	routeErrorCode:      auth.ScopePublic,
+	routeLogLevel:       auth.ScopeAdmin,
+	routePutLogLevel:    auth.ScopeAdmin,
+}
+
 type Router struct {
-	server *http.Server
-	Conf   *RouterConfig
-	logger logger.Logger
+	server      *http.Server
+	Conf        *RouterConfig
+	logger      logger.Logger
+	keyStore    auth.KeyStore
+	jwtVerifier *auth.JWTVerifier
+	// RateLimitRejections optional counter of the requests rejected by the rate limiter
+	RateLimitRejections *prometheus.CounterVec
+	// Tracer optional tracer of the root span of the requests
+	Tracer tracer.Tracer
+	// LogLevels optional runtime log levels, served on /admin/log-level when set and authentication is enabled
+	LogLevels *log.Levels
+	// AccessLogSink optional sink of the access log, written to the standard output when the access log is enabled
+	AccessLogSink log.Sink
 }
 
-func (r *Router) Init(l logger.Logger, articleService services.ArticleService, latencyReport *prometheus.SummaryVec) {
+func (r *Router) Init(l logger.Logger, articleService services.ArticleService, latencyReport *prometheus.SummaryVec) error {
 	muxRouter := mux.NewRouter()
 	r.logger = l
 
-	errorHandler := handlers.ErrorHandler{Log: l}
+	errorHandler := handlers.ErrorHandler{Log: l, ProblemTypeBase: r.Conf.Problem.TypeBase}
 
 	r.server = &http.Server{
 		Addr:         fmt.Sprintf(":%s", r.Conf.Host),
-		Handler:      muxRouter,
+		Handler:      r.accessLogHandler(r.corsHandler(muxRouter)),
 		ReadTimeout:  r.Conf.Timeouts.Read,
 		WriteTimeout: r.Conf.Timeouts.Write,
 		IdleTimeout:  r.Conf.Timeouts.Idle,
 	}
-	mw := handlers.Middleware{Logger: l}
+	mw := handlers.Middleware{Logger: l, Tracer: r.Tracer}
 	muxRouter.Use(mw.MiddleFunc)
+	nm := handlers.NegotiationMiddleware{Registry: encoders.DefaultRegistry(), ErrorHandler: errorHandler}
+	muxRouter.Use(nm.MiddleFunc)
+	if r.Conf.Compression.Enabled {
+		cm := handlers.NewCompressionMiddleware(errorHandler, r.Conf.Compression.Level, r.Conf.Compression.MinSize,
+			r.Conf.Compression.ContentTypes)
+		muxRouter.Use(cm.MiddleFunc)
+	}
+	authenticators, err := r.authenticators()
+	if err != nil {
+		return err
+	}
+	var clientLimit handlers.RateLimitMiddleware
+	if r.Conf.RateLimit.Enabled {
+		var ipLimit handlers.RateLimitMiddleware
+		ipLimit, clientLimit, err = r.rateLimitMiddlewares(errorHandler)
+		if err != nil {
+			return err
+		}
+		// limit the ips before the authentication, so that unauthenticated floods and guessed credentials are
+		// throttled too
+		if len(authenticators) > 0 {
+			muxRouter.Use(ipLimit.MiddleFunc)
+		}
+	}
+	if len(authenticators) > 0 {
+		am := handlers.AuthMiddleware{
+			Log:            l,
+			Authenticators: authenticators,
+			RouteScopes:    routeScopes,
+			ErrorHandler:   errorHandler,
+		}
+		muxRouter.Use(am.MiddleFunc)
+	}
+	// limit the bodies after the compression middleware to limit the decompressed bodies
+	if r.Conf.Decoding.MaxBodySize > 0 {
+		bm := handlers.BodyLimitMiddleware{MaxBytes: r.Conf.Decoding.MaxBodySize, ErrorHandler: errorHandler}
+		muxRouter.Use(bm.MiddleFunc)
+	}
+	if r.Conf.RateLimit.Enabled {
+		muxRouter.Use(clientLimit.MiddleFunc)
+	}
+
+	// idempotency keys are only kept when a ttl is configured
+	var idempotencyStore idempotency.Store
+	if r.Conf.Idempotency.TTL > 0 {
+		idempotencyStore = idempotency.NewMemoryStore(r.Conf.Idempotency.TTL)
+	}
 
 	muxRouter.Handle(
 		"/articles",
@@ -43,7 +137,34 @@ func (r *Router) Init(l logger.Logger, articleService services.ArticleService, l
 			ArticleService:       articleService,
 			ErrorHandler:         errorHandler,
 			RequestLatencyReport: latencyReport,
-		}).Methods(http.MethodPost)
+			IdempotencyStore:     idempotencyStore,
+			StrictDecoding:       r.Conf.Decoding.Strict,
+		}).Methods(http.MethodPost).Name(routeCreateArticle)
+
+	batchGetHandler := handlers.ArticleBatchGetHandler{
+		Log:                  l,
+		ArticleService:       articleService,
+		ErrorHandler:         errorHandler,
+		RequestLatencyReport: latencyReport,
+		MaxIDs:               r.Conf.Batch.MaxIDs,
+		StrictDecoding:       r.Conf.Decoding.Strict,
+	}
+	muxRouter.Handle("/articles", batchGetHandler).
+		Queries(handlers.QueryParameterIDs, "{ids}").
+		Methods(http.MethodGet).
+		Name(routeBatchArticles)
+	muxRouter.Handle("/articles/batch", batchGetHandler).Methods(http.MethodPost).Name(routeBatchPost)
+
+	muxRouter.Handle(
+		"/articles",
+		handlers.ArticleListHandler{
+			Log:                  l,
+			ArticleService:       articleService,
+			ErrorHandler:         errorHandler,
+			RequestLatencyReport: latencyReport,
+			DefaultLimit:         r.Conf.List.DefaultLimit,
+			MaxLimit:             r.Conf.List.MaxLimit,
+		}).Methods(http.MethodGet).Name(routeListArticles)
 
 	muxRouter.Handle(
 		"/articles/{id}",
@@ -52,7 +173,17 @@ func (r *Router) Init(l logger.Logger, articleService services.ArticleService, l
 			ArticleService:       articleService,
 			ErrorHandler:         errorHandler,
 			RequestLatencyReport: latencyReport,
-		}).Methods(http.MethodGet)
+			CacheControl:         r.Conf.CacheControl.Article,
+		}).Methods(http.MethodGet).Name(routeGetArticle)
+	muxRouter.Handle(
+		"/articles/{id}",
+		handlers.ArticlePutHandler{
+			Log:                  l,
+			ArticleService:       articleService,
+			ErrorHandler:         errorHandler,
+			RequestLatencyReport: latencyReport,
+			StrictDecoding:       r.Conf.Decoding.Strict,
+		}).Methods(http.MethodPut).Name(routePutArticle)
 	muxRouter.Handle(
 		"/tags/{tagName}/{date}",
 		handlers.ArticleFilterHandler{
@@ -60,11 +191,155 @@ func (r *Router) Init(l logger.Logger, articleService services.ArticleService, l
 			ArticleService:       articleService,
 			ErrorHandler:         errorHandler,
 			RequestLatencyReport: latencyReport,
-		}).Methods(http.MethodGet)
+			CacheControl:         r.Conf.CacheControl.Tags,
+		}).Methods(http.MethodGet).Name(routeFilterArticles)
+
+	errorCatalogueHandler := handlers.ErrorCatalogueHandler{
+		Log:                  l,
+		ErrorHandler:         errorHandler,
+		RequestLatencyReport: latencyReport,
+	}
+	muxRouter.Handle("/errors", errorCatalogueHandler).Methods(http.MethodGet).Name(routeErrorCatalogue)
+	muxRouter.Handle("/errors/{code}", errorCatalogueHandler).Methods(http.MethodGet).Name(routeErrorCode)
+
+	// the admin routes are only served with authentication, which checks their admin scope
+	if r.LogLevels != nil && len(authenticators) > 0 {
+		logLevelHandler := handlers.LogLevelHandler{
+			Log:                  l,
+			Levels:               r.LogLevels,
+			ErrorHandler:         errorHandler,
+			RequestLatencyReport: latencyReport,
+		}
+		muxRouter.Handle("/admin/log-level", logLevelHandler).Methods(http.MethodGet).Name(routeLogLevel)
+		muxRouter.Handle("/admin/log-level", logLevelHandler).Methods(http.MethodPut).Name(routePutLogLevel)
+	}
+	return nil
+}
+
+// corsHandler - wrap the router with the cors middleware when enabled, so that preflight requests are answered
+// before the routes are matched
+func (r *Router) corsHandler(muxRouter *mux.Router) http.Handler {
+	if !r.Conf.CORS.Enabled {
+		return muxRouter
+	}
+	cm := handlers.CORSMiddleware{
+		AllowedOrigins:   r.Conf.CORS.AllowedOrigins,
+		AllowedMethods:   r.Conf.CORS.AllowedMethods,
+		AllowedHeaders:   r.Conf.CORS.AllowedHeaders,
+		ExposedHeaders:   r.Conf.CORS.ExposedHeaders,
+		AllowCredentials: r.Conf.CORS.AllowCredentials,
+		MaxAge:           r.Conf.CORS.MaxAge,
+	}
+	return cm.MiddleFunc(muxRouter)
+}
+
+// accessLogHandler - wrap the handler with the access log middleware when enabled, outside of the router so that
+// unmatched routes and preflight requests are logged too
+func (r *Router) accessLogHandler(handler http.Handler) http.Handler {
+	if !r.Conf.AccessLog.Enabled {
+		return handler
+	}
+	sink := r.AccessLogSink
+	if sink == nil {
+		sink = log.NewWriterSink(os.Stdout)
+	}
+	am := handlers.AccessLogMiddleware{
+		Sink:          sink,
+		Format:        r.Conf.AccessLog.Format,
+		SampleRate:    r.Conf.AccessLog.SampleRate,
+		SlowThreshold: r.Conf.AccessLog.SlowThreshold,
+	}
+	return am.MiddleFunc(handler)
+}
+
+// authenticators - enabled authenticators, bearer tokens are checked before the api keys
+func (r *Router) authenticators() ([]auth.Authenticator, error) {
+	var authenticators []auth.Authenticator
+	if r.Conf.JWT.Enabled {
+		verifier, err := auth.NewJWTVerifier(auth.JWTConfig{
+			Secret:        r.Conf.JWT.Secret,
+			PublicKeyFile: r.Conf.JWT.PublicKeyFile,
+			JWKSFile:      r.Conf.JWT.JWKSFile,
+			Issuer:        r.Conf.JWT.Issuer,
+			Audience:      r.Conf.JWT.Audience,
+			Leeway:        r.Conf.JWT.Leeway,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error loading jwt keys due to, %w", err)
+		}
+		r.jwtVerifier = verifier
+		authenticators = append(authenticators, auth.BearerAuthenticator{Verifier: verifier})
+	}
+	if r.Conf.Auth.Enabled {
+		keyStore, err := auth.NewFileKeyStore(r.Conf.Auth.KeysFile)
+		if err != nil {
+			return nil, fmt.Errorf("error loading api keys due to, %w", err)
+		}
+		r.keyStore = keyStore
+		authenticators = append(authenticators, auth.APIKeyAuthenticator{Store: keyStore, Header: r.Conf.Auth.Header})
+	}
+	return authenticators, nil
+}
+
+// rateLimitMiddlewares - rate limiters sharing the buckets, the ip limiter of the requests before the authentication
+// and the client limiter with the default and per route limits, keyed by the route names
+func (r *Router) rateLimitMiddlewares(errorHandler handlers.ErrorHandler) (ip, client handlers.RateLimitMiddleware,
+	err error) {
+	ipLimit, err := ratelimit.ParseLimit(r.Conf.RateLimit.IP)
+	if err != nil {
+		return ip, client, err
+	}
+	defaultLimit, err := ratelimit.ParseLimit(r.Conf.RateLimit.Default)
+	if err != nil {
+		return ip, client, err
+	}
+	routeLimits, err := ratelimit.ParseRouteLimits(r.Conf.RateLimit.Routes)
+	if err != nil {
+		return ip, client, err
+	}
+	maxPeriod := defaultLimit.Period
+	if ipLimit.Period > maxPeriod {
+		maxPeriod = ipLimit.Period
+	}
+	for _, limit := range routeLimits {
+		if limit.Period > maxPeriod {
+			maxPeriod = limit.Period
+		}
+	}
+
+	limiter := ratelimit.NewLimiter(maxPeriod)
+	ip = handlers.RateLimitMiddleware{
+		Limiter:      limiter,
+		Default:      ipLimit,
+		Rejections:   r.RateLimitRejections,
+		ErrorHandler: errorHandler,
+		ByIP:         true,
+	}
+	client = handlers.RateLimitMiddleware{
+		Limiter:      limiter,
+		Default:      defaultLimit,
+		RouteLimits:  routeLimits,
+		Rejections:   r.RateLimitRejections,
+		ErrorHandler: errorHandler,
+	}
+	return ip, client, nil
+}
+
+// ReloadKeys reload the api keys and the jwt verification keys when their authentication is enabled
+func (r *Router) ReloadKeys() error {
+	if r.keyStore != nil {
+		if err := r.keyStore.Reload(); err != nil {
+			return err
+		}
+	}
+	if r.jwtVerifier != nil {
+		return r.jwtVerifier.Reload()
+	}
+	return nil
 }
 
 func (r *Router) Start() error {
-	r.logger.Info(fmt.Sprintf("server starting on port: %s", r.Conf.Host))
+	r.logger.Info("server starting", logger.F("port", r.Conf.Host))
 	if err := r.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		return err
 	}
@@ -74,6 +349,6 @@ func (r *Router) Start() error {
 func (r *Router) Stop() error {
 	c, fn := context.WithTimeout(context.Background(), r.Conf.Timeouts.ShoutDownWait)
 	defer fn()
-	r.logger.Info(fmt.Sprintf("server shutting down on port: %s", r.Conf.Host))
+	r.logger.Info("server shutting down", logger.F("port", r.Conf.Host))
 	return r.server.Shutdown(c)
 }
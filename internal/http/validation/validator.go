@@ -0,0 +1,133 @@
+package validation
+
+import (
+	"article-dispatcher/internal/http/responses"
+
+	"github.com/go-playground/validator/v10"
+
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// tagPattern allowed characters of the article tags
+var tagPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Validator validate the request payloads with the rules of their `validate` tags, safe for concurrent use
+type Validator struct {
+	validate *validator.Validate
+	now      func() time.Time
+}
+
+// New create a validator with the custom rules registered
+// tagname - only letters, digits, '.', '_' and '-', starting with a letter or a digit
+// maxfuture - `2006-01-02` date at most the duration param after today
+func New() *Validator {
+	v := &Validator{
+		validate: validator.New(),
+		now:      time.Now,
+	}
+	// report the fields by their json names
+	v.validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	mustRegister(v.validate, "tagname", func(fl validator.FieldLevel) bool {
+		return tagPattern.MatchString(fl.Field().String())
+	})
+	mustRegister(v.validate, "maxfuture", v.maxFuture)
+	return v
+}
+
+func mustRegister(validate *validator.Validate, tag string, fn validator.Func) {
+	if err := validate.RegisterValidation(tag, fn); err != nil {
+		panic(fmt.Sprintf("error registering the %s validation due to, %s", tag, err))
+	}
+}
+
+// maxFuture check if the date is at most the duration param after today, unparsable dates are left to the
+// datetime rule
+func (v *Validator) maxFuture(fl validator.FieldLevel) bool {
+	limit, err := time.ParseDuration(fl.Param())
+	if err != nil {
+		panic(fmt.Sprintf("invalid maxfuture param [%s]", fl.Param()))
+	}
+	date, err := time.Parse("2006-01-02", fl.Field().String())
+	if err != nil {
+		return true
+	}
+	return !date.After(v.now().UTC().Add(limit))
+}
+
+// Struct validate the struct, returning the failed rules of its fields
+func (v *Validator) Struct(s interface{}) ([]responses.FieldError, error) {
+	err := v.validate.Struct(s)
+	if err == nil {
+		return nil, nil
+	}
+	validationErrors, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return nil, err
+	}
+
+	fields := make([]responses.FieldError, 0, len(validationErrors))
+	for _, fe := range validationErrors {
+		// drop the struct name from the namespace, e.g. `Article.tags[0]`
+		field := fe.Namespace()
+		if i := strings.Index(field, "."); i >= 0 {
+			field = field[i+1:]
+		}
+		fields = append(fields, responses.FieldError{
+			Field:   field,
+			Rule:    fe.Tag(),
+			Message: message(field, fe),
+		})
+	}
+	return fields, nil
+}
+
+// message readable message of the failed rule
+func message(field string, fe validator.FieldError) string {
+	items := "characters"
+	if fe.Kind() == reflect.Slice || fe.Kind() == reflect.Map {
+		items = "items"
+	}
+	switch fe.Tag() {
+	case "required":
+		return fmt.Sprintf("%s is required", field)
+	case "required_with":
+		return fmt.Sprintf("%s is required when %s is set", field, toSnakeCase(fe.Param()))
+	case "min":
+		return fmt.Sprintf("%s must have at least %s %s", field, fe.Param(), items)
+	case "max":
+		return fmt.Sprintf("%s must have at most %s %s", field, fe.Param(), items)
+	case "unique":
+		return fmt.Sprintf("%s must not contain duplicates", field)
+	case "datetime":
+		return fmt.Sprintf("%s must be a date in the %s format", field, fe.Param())
+	case "maxfuture":
+		return fmt.Sprintf("%s must not be more than %s in the future", field, fe.Param())
+	case "tagname":
+		return fmt.Sprintf("%s must only contain letters, digits, '.', '_' or '-' and start with a letter or digit", field)
+	default:
+		return fmt.Sprintf("%s failed the %s rule", field, fe.Tag())
+	}
+}
+
+// toSnakeCase json name of the struct field param, e.g. `ExternalID` to `external_id`
+func toSnakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		upper := r >= 'A' && r <= 'Z'
+		if upper && i > 0 && !(name[i-1] >= 'A' && name[i-1] <= 'Z') {
+			b.WriteByte('_')
+		}
+		b.WriteRune(r)
+	}
+	return strings.ToLower(b.String())
+}
@@ -0,0 +1,68 @@
+package validation
+
+import (
+	"article-dispatcher/internal/domain/models"
+	"article-dispatcher/internal/http/responses"
+
+	"github.com/stretchr/testify/assert"
+
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidator_Struct(t *testing.T) {
+	v := New()
+	v.now = func() time.Time { return time.Date(2023, 3, 30, 0, 0, 0, 0, time.UTC) }
+	valid := func() models.Article {
+		return models.Article{Title: "title", Date: "2023-03-30", Body: "body", Tags: []string{"nature", "fit-ness"}}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(a *models.Article)
+		want   []responses.FieldError
+	}{
+		{name: "valid", modify: func(a *models.Article) {}},
+		{name: "missing fields", modify: func(a *models.Article) { *a = models.Article{} }, want: []responses.FieldError{
+			{Field: "title", Rule: "required", Message: "title is required"},
+			{Field: "date", Rule: "required", Message: "date is required"},
+			{Field: "body", Rule: "required", Message: "body is required"},
+			{Field: "tags", Rule: "required", Message: "tags is required"},
+		}},
+		{name: "long title", modify: func(a *models.Article) { a.Title = strings.Repeat("a", 256) }, want: []responses.FieldError{
+			{Field: "title", Rule: "max", Message: "title must have at most 255 characters"},
+		}},
+		{name: "far future date", modify: func(a *models.Article) { a.Date = "2025-01-01" }, want: []responses.FieldError{
+			{Field: "date", Rule: "maxfuture", Message: "date must not be more than 8760h in the future"},
+		}},
+		{name: "invalid date", modify: func(a *models.Article) { a.Date = "30-03-2023" }, want: []responses.FieldError{
+			{Field: "date", Rule: "datetime", Message: "date must be a date in the 2006-01-02 format"},
+		}},
+		{name: "invalid tags", modify: func(a *models.Article) { a.Tags = []string{"ok", "not ok", ""} }, want: []responses.FieldError{
+			{Field: "tags[1]", Rule: "tagname",
+				Message: "tags[1] must only contain letters, digits, '.', '_' or '-' and start with a letter or digit"},
+			{Field: "tags[2]", Rule: "required", Message: "tags[2] is required"},
+		}},
+		{name: "duplicated tags", modify: func(a *models.Article) { a.Tags = []string{"a", "a"} }, want: []responses.FieldError{
+			{Field: "tags", Rule: "unique", Message: "tags must not contain duplicates"},
+		}},
+		{name: "too many tags", modify: func(a *models.Article) {
+			a.Tags = strings.Split(strings.Repeat("t,", 20)+"t", ",")
+		}, want: []responses.FieldError{
+			{Field: "tags", Rule: "max", Message: "tags must have at most 20 items"},
+		}},
+		{name: "external id without source", modify: func(a *models.Article) { a.ExternalID = "a1" }, want: []responses.FieldError{
+			{Field: "source", Rule: "required_with", Message: "source is required when external_id is set"},
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			a := valid()
+			test.modify(&a)
+			got, err := v.Struct(&a)
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
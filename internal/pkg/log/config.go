@@ -5,14 +5,48 @@ import (
 	"github.com/pkg/errors"
 
 	"log"
+	"os"
+	"strings"
+	"time"
 )
 
 var Config LoggerConfig
 
 type LoggerConfig struct {
 	Level string `env:"LOG_LEVEL" envDefault:"TRACE"`
+	// Format of the log entries, one of text, json or logfmt
+	Format      string `env:"LOG_FORMAT" envDefault:"text"`
+	ServiceName string `env:"LOG_SERVICE_NAME" envDefault:"article-dispatcher"`
+	// ConfigFile optional json file of the levels, applied at boot and re-read on SIGHUP
+	ConfigFile string `env:"LOG_CONFIG_FILE"`
+	// OverrideTTL ttl of the package level overrides which do not set their own
+	OverrideTTL time.Duration `env:"LOG_OVERRIDE_TTL" envDefault:"15m"`
+	// Sinks destinations of the entries, stdout, stderr, file or syslog, optionally with their minimum level
+	// e.g. `stderr:INFO`
+	Sinks []string `env:"LOG_SINKS" envDefault:"stderr"`
+	// BufferSize entries buffered per sink to be written in the background, zero writes synchronously
+	BufferSize int `env:"LOG_BUFFER_SIZE" envDefault:"4096"`
+	File       struct {
+		Path       string        `env:"LOG_FILE_PATH" envDefault:"logs/article-dispatcher.log"`
+		MaxSize    int64         `env:"LOG_FILE_MAX_SIZE" envDefault:"104857600"`
+		MaxAge     time.Duration `env:"LOG_FILE_MAX_AGE" envDefault:"24h"`
+		MaxBackups int           `env:"LOG_FILE_MAX_BACKUPS" envDefault:"7"`
+		Compress   bool          `env:"LOG_FILE_COMPRESS" envDefault:"true"`
+	}
+	Syslog struct {
+		Socket string `env:"LOG_SYSLOG_SOCKET" envDefault:"/dev/log"`
+		Tag    string `env:"LOG_SYSLOG_TAG" envDefault:"article-dispatcher"`
+	}
 }
 
+// sinks
+const (
+	SinkStdout = "stdout"
+	SinkStderr = "stderr"
+	SinkFile   = "file"
+	SinkSyslog = "syslog"
+)
+
 // Register log configurations
 func (l *LoggerConfig) Register() error {
 	err := env.Parse(&Config)
@@ -24,9 +58,93 @@ func (l *LoggerConfig) Register() error {
 
 // Validate log configurations
 func (l *LoggerConfig) Validate() error {
+	if _, ok := formatters[Config.Format]; !ok {
+		return errors.Errorf("LOG_FORMAT [%s] must be one of %s, %s or %s", Config.Format, FormatText, FormatJSON,
+			FormatLogfmt)
+	}
+	if Config.OverrideTTL <= 0 {
+		return errors.New("LOG_OVERRIDE_TTL must be positive")
+	}
+	if _, err := Config.sinks(); err != nil {
+		return err
+	}
+	if Config.BufferSize < 0 {
+		return errors.New("LOG_BUFFER_SIZE cannot be negative")
+	}
+	if Config.File.MaxSize < 0 || Config.File.MaxAge < 0 || Config.File.MaxBackups < 0 {
+		return errors.New("LOG_FILE_MAX_SIZE, LOG_FILE_MAX_AGE and LOG_FILE_MAX_BACKUPS cannot be negative")
+	}
 	return nil
 }
 
+// NewSink sink of the configured sinks, each buffered when a buffer size is set
+func (l LoggerConfig) NewSink() (Sink, error) {
+	entries, err := l.sinks()
+	if err != nil {
+		return nil, err
+	}
+	fanout := make(FanoutSink, 0, len(entries))
+	for _, e := range entries {
+		var sink Sink
+		switch e.name {
+		case SinkStdout:
+			sink = NewWriterSink(os.Stdout)
+		case SinkStderr:
+			sink = NewWriterSink(os.Stderr)
+		case SinkFile:
+			sink, err = NewFileSink(l.File.Path, l.File.MaxSize, l.File.MaxAge, l.File.MaxBackups, l.File.Compress)
+		case SinkSyslog:
+			sink, err = NewSyslogSink(l.Syslog.Socket, l.Syslog.Tag)
+		}
+		if err != nil {
+			_ = fanout.Close()
+			return nil, err
+		}
+		if l.BufferSize > 0 {
+			sink = NewAsyncSink(sink, l.BufferSize, l.Format, l.ServiceName)
+		}
+		fanout = append(fanout, LevelSink{Sink: sink, Level: e.level})
+	}
+	return fanout, nil
+}
+
+// sinkEntry configured sink and its minimum level
+type sinkEntry struct {
+	name  string
+	level string
+}
+
+// sinks parse the `name[:LEVEL]` sink entries, sinks without a level receive all of the entries
+func (l LoggerConfig) sinks() ([]sinkEntry, error) {
+	if len(l.Sinks) == 0 {
+		return nil, errors.New("LOG_SINKS cannot be empty")
+	}
+	sinks := make([]sinkEntry, 0, len(l.Sinks))
+	seen := make(map[string]bool, len(l.Sinks))
+	for _, entry := range l.Sinks {
+		parts := strings.SplitN(strings.TrimSpace(entry), ":", 2)
+		name, level := parts[0], TRACE
+		if len(parts) == 2 {
+			level = parts[1]
+		}
+		switch name {
+		case SinkStdout, SinkStderr, SinkFile, SinkSyslog:
+		default:
+			return nil, errors.Errorf("LOG_SINKS entry [%s] must be one of %s, %s, %s or %s", entry, SinkStdout,
+				SinkStderr, SinkFile, SinkSyslog)
+		}
+		if _, ok := LevelMap[level]; !ok {
+			return nil, errors.Errorf("LOG_SINKS entry [%s] has an invalid level", entry)
+		}
+		if seen[name] {
+			return nil, errors.Errorf("LOG_SINKS sink [%s] is configured more than once", name)
+		}
+		seen[name] = true
+		sinks = append(sinks, sinkEntry{name: name, level: level})
+	}
+	return sinks, nil
+}
+
 // Print log configurations
 func (l *LoggerConfig) Print() interface{} {
 	defer log.Println("---loading logger configs---")
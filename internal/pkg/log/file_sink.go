@@ -0,0 +1,179 @@
+package log
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// backupTimeFormat suffix of the rotated files, sorting them by their rotation time
+	backupTimeFormat = "20060102T150405.000"
+	logDirMode       = 0o755
+	logFileMode      = 0o644
+)
+
+// FileSink sink appending the entries to a file, rotated once it reaches its maximum size or age. Rotated files are
+// renamed with their rotation time, optionally gzipped in the background, and only the latest backups are kept
+type FileSink struct {
+	path       string
+	maxSize    int64
+	maxAge     time.Duration
+	maxBackups int
+	compress   bool
+
+	lock     sync.Mutex
+	file     *os.File
+	size     int64
+	opened   time.Time
+	rotating sync.WaitGroup
+	// pruning backups of concurrent rotations are compressed and pruned one at a time
+	pruning sync.Mutex
+}
+
+// NewFileSink sink of the file at path, a zero maxSize or maxAge disables the rotation on size or age and
+// a zero maxBackups keeps all of the backups
+func NewFileSink(path string, maxSize int64, maxAge time.Duration, maxBackups int, compress bool) (*FileSink, error) {
+	fs := &FileSink{path: path, maxSize: maxSize, maxAge: maxAge, maxBackups: maxBackups, compress: compress}
+	if err := fs.open(); err != nil {
+		return nil, err
+	}
+	return fs, nil
+}
+
+func (fs *FileSink) Write(_ string, entry []byte) error {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+	if fs.file == nil {
+		return fmt.Errorf("log file [%s] is closed", fs.path)
+	}
+	if fs.size > 0 && ((fs.maxSize > 0 && fs.size+int64(len(entry)) > fs.maxSize) ||
+		(fs.maxAge > 0 && time.Since(fs.opened) >= fs.maxAge)) {
+		if err := fs.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := fs.file.Write(entry)
+	fs.size += int64(n)
+	return err
+}
+
+// Close close the file and wait for the rotated files to be compressed
+func (fs *FileSink) Close() error {
+	fs.lock.Lock()
+	var err error
+	if fs.file != nil {
+		err = fs.file.Close()
+		fs.file = nil
+	}
+	fs.lock.Unlock()
+	fs.rotating.Wait()
+	return err
+}
+
+func (fs *FileSink) open() error {
+	if err := os.MkdirAll(filepath.Dir(fs.path), logDirMode); err != nil {
+		return fmt.Errorf("error creating the log directory of [%s] due to, %w", fs.path, err)
+	}
+	file, err := os.OpenFile(fs.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, logFileMode)
+	if err != nil {
+		return fmt.Errorf("error opening log file [%s] due to, %w", fs.path, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("error reading log file [%s] due to, %w", fs.path, err)
+	}
+	fs.file, fs.size, fs.opened = file, info.Size(), time.Now()
+	return nil
+}
+
+// rotate rename the current file with its rotation time and open a new one
+func (fs *FileSink) rotate() error {
+	if err := fs.file.Close(); err != nil {
+		return fmt.Errorf("error closing log file [%s] due to, %w", fs.path, err)
+	}
+	fs.file = nil
+	backup := fs.path + "." + time.Now().Format(backupTimeFormat)
+	if err := os.Rename(fs.path, backup); err != nil {
+		return fmt.Errorf("error rotating log file [%s] due to, %w", fs.path, err)
+	}
+	if err := fs.open(); err != nil {
+		return err
+	}
+
+	fs.rotating.Add(1)
+	go func() {
+		defer fs.rotating.Done()
+		fs.pruning.Lock()
+		defer fs.pruning.Unlock()
+		if fs.compress {
+			if err := compressFile(backup); err != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "failed to compress log file [%s] due to, %s\n", backup, err)
+			}
+		}
+		if err := fs.prune(); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "failed to remove old log files of [%s] due to, %s\n", fs.path, err)
+		}
+	}()
+	return nil
+}
+
+// prune remove the oldest backups above the maximum number of backups
+func (fs *FileSink) prune() error {
+	if fs.maxBackups <= 0 {
+		return nil
+	}
+	matches, err := filepath.Glob(fs.path + ".*")
+	if err != nil {
+		return err
+	}
+	var backups []string
+	for _, match := range matches {
+		suffix := strings.TrimSuffix(strings.TrimPrefix(match, fs.path+"."), ".gz")
+		if _, err := time.Parse(backupTimeFormat, suffix); err == nil {
+			backups = append(backups, match)
+		}
+	}
+	sort.Strings(backups)
+	for len(backups) > fs.maxBackups {
+		if err := os.Remove(backups[0]); err != nil {
+			return err
+		}
+		backups = backups[1:]
+	}
+	return nil
+}
+
+// compressFile gzip the file to a `.gz` file and remove it
+func compressFile(path string) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, logFileMode)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(out)
+	if _, err = io.Copy(zw, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	if err = zw.Close(); err != nil {
+		_ = out.Close()
+		return err
+	}
+	if err = out.Close(); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
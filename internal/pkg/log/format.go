@@ -0,0 +1,171 @@
+package log
+
+import (
+	domLogger "article-dispatcher/internal/domain/adaptors/logger"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// log formats
+const (
+	FormatText   = "text"
+	FormatJSON   = "json"
+	FormatLogfmt = "logfmt"
+)
+
+// reserved keys of the json and logfmt entries, fields using them are prefixed with `field.`
+const (
+	keyTime    = "ts"
+	keyLevel   = "level"
+	keyService = "service"
+	keyCaller  = "caller"
+	keyMessage = "msg"
+)
+
+// entry log entry to be formatted
+type entry struct {
+	Time    time.Time
+	Level   string
+	Service string
+	Caller  string
+	Message string
+	Fields  []domLogger.Field
+}
+
+var formatters = map[string]func(e entry) []byte{
+	FormatText:   formatText,
+	FormatJSON:   formatJSON,
+	FormatLogfmt: formatLogfmt,
+}
+
+// formatText `2006/01/02 15:04:05 [LEVEL]:  message key=value` format of the standard logger
+func formatText(e entry) []byte {
+	var b bytes.Buffer
+	b.WriteString(e.Time.Format("2006/01/02 15:04:05"))
+	b.WriteString(" [")
+	b.WriteString(e.Level)
+	b.WriteString("]:  ")
+	b.WriteString(e.Message)
+	for _, f := range e.Fields {
+		b.WriteByte(' ')
+		writeLogfmtPair(&b, f.Key, fieldValue(f.Value))
+	}
+	b.WriteByte('\n')
+	return b.Bytes()
+}
+
+// formatJSON one json object per entry, keeping the order of the fields
+func formatJSON(e entry) []byte {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	writeJSONPair(&b, keyTime, e.Time.UTC().Format(time.RFC3339Nano))
+	b.WriteByte(',')
+	writeJSONPair(&b, keyLevel, strings.ToLower(e.Level))
+	if e.Service != "" {
+		b.WriteByte(',')
+		writeJSONPair(&b, keyService, e.Service)
+	}
+	if e.Caller != "" {
+		b.WriteByte(',')
+		writeJSONPair(&b, keyCaller, e.Caller)
+	}
+	b.WriteByte(',')
+	writeJSONPair(&b, keyMessage, e.Message)
+	for _, f := range e.Fields {
+		b.WriteByte(',')
+		writeJSONPair(&b, fieldKey(f.Key), fieldValue(f.Value))
+	}
+	b.WriteString("}\n")
+	return b.Bytes()
+}
+
+// formatLogfmt one line of key=value pairs per entry
+func formatLogfmt(e entry) []byte {
+	var b bytes.Buffer
+	writeLogfmtPair(&b, keyTime, e.Time.UTC().Format(time.RFC3339Nano))
+	b.WriteByte(' ')
+	writeLogfmtPair(&b, keyLevel, strings.ToLower(e.Level))
+	if e.Service != "" {
+		b.WriteByte(' ')
+		writeLogfmtPair(&b, keyService, e.Service)
+	}
+	if e.Caller != "" {
+		b.WriteByte(' ')
+		writeLogfmtPair(&b, keyCaller, e.Caller)
+	}
+	b.WriteByte(' ')
+	writeLogfmtPair(&b, keyMessage, e.Message)
+	for _, f := range e.Fields {
+		b.WriteByte(' ')
+		writeLogfmtPair(&b, fieldKey(f.Key), fieldValue(f.Value))
+	}
+	b.WriteByte('\n')
+	return b.Bytes()
+}
+
+func writeJSONPair(b *bytes.Buffer, key string, value interface{}) {
+	k, _ := json.Marshal(key)
+	b.Write(k)
+	b.WriteByte(':')
+	v, err := json.Marshal(value)
+	if err != nil {
+		v, _ = json.Marshal(fmt.Sprint(value))
+	}
+	b.Write(v)
+}
+
+func writeLogfmtPair(b *bytes.Buffer, key string, value interface{}) {
+	b.WriteString(key)
+	b.WriteByte('=')
+	var s string
+	switch v := value.(type) {
+	case string:
+		s = v
+	case nil:
+		s = "null"
+	default:
+		s = fmt.Sprint(v)
+	}
+	if needsQuoting(s) {
+		s = strconv.Quote(s)
+	}
+	b.WriteString(s)
+}
+
+// needsQuoting check whether the logfmt value has to be quoted to be parsed back
+func needsQuoting(s string) bool {
+	if s == "" {
+		return true
+	}
+	for _, r := range s {
+		if r == '=' || r == '"' || unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldKey key of the field, prefixed when it is one of the reserved keys of the entry
+func fieldKey(key string) string {
+	switch key {
+	case keyTime, keyLevel, keyService, keyCaller, keyMessage:
+		return "field." + key
+	}
+	return key
+}
+
+// fieldValue loggable value of the field, errors and stringers are logged as their text
+func fieldValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case error:
+		return v.Error()
+	case fmt.Stringer:
+		return v.String()
+	}
+	return value
+}
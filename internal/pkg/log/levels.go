@@ -0,0 +1,212 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Levels log levels changeable at runtime, shared by a logger and its children. Packages can be logged at their own
+// level for a while, after which they revert to the level of the logger
+type Levels struct {
+	level      int32
+	defaultTTL time.Duration
+	lock       sync.RWMutex
+	overrides  map[string]override
+	// active number of overrides, checked before resolving the package of the caller
+	active int32
+}
+
+type override struct {
+	level   int
+	expires time.Time
+}
+
+// Override level of the package until it expires, packages are the directories below `internal`
+// e.g. `adaptors/cache`, matching their sub packages as well
+type Override struct {
+	Package   string    `json:"package"`
+	Level     string    `json:"level"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// LevelConfig levels to apply, overrides without a ttl last the default ttl of the levels
+type LevelConfig struct {
+	Level     string                `json:"level"`
+	Overrides []LevelOverrideConfig `json:"overrides"`
+}
+
+// LevelOverrideConfig override of a package, the ttl is a duration e.g. `15m`
+type LevelOverrideConfig struct {
+	Package string `json:"package"`
+	Level   string `json:"level"`
+	TTL     string `json:"ttl"`
+}
+
+// LevelState levels in effect
+type LevelState struct {
+	Level     string     `json:"level"`
+	Overrides []Override `json:"overrides"`
+}
+
+// NewLevels runtime levels starting at the level, overrides last defaultTTL unless given their own ttl
+func NewLevels(level string, defaultTTL time.Duration) (*Levels, error) {
+	l, ok := LevelMap[level]
+	if !ok {
+		return nil, fmt.Errorf("invalid log level received [%s]", level)
+	}
+	if defaultTTL <= 0 {
+		return nil, fmt.Errorf("invalid log level override ttl received [%s]", defaultTTL)
+	}
+	return &Levels{level: int32(l), defaultTTL: defaultTTL, overrides: make(map[string]override)}, nil
+}
+
+// Level level of the logger
+func (lv *Levels) Level() string {
+	return levelName(int(atomic.LoadInt32(&lv.level)))
+}
+
+// SetLevel change the level of the logger
+func (lv *Levels) SetLevel(level string) error {
+	l, ok := LevelMap[level]
+	if !ok {
+		return fmt.Errorf("invalid log level received [%s]", level)
+	}
+	atomic.StoreInt32(&lv.level, int32(l))
+	return nil
+}
+
+// Apply set the level and replace the overrides of the configuration, nothing is changed when it is invalid
+func (lv *Levels) Apply(c LevelConfig) error {
+	level, ok := LevelMap[c.Level]
+	if !ok {
+		return fmt.Errorf("invalid log level received [%s]", c.Level)
+	}
+	now := time.Now()
+	overrides := make(map[string]override, len(c.Overrides))
+	for _, o := range c.Overrides {
+		pkg := strings.Trim(o.Package, "/")
+		if pkg == "" {
+			return fmt.Errorf("log level override package cannot be empty")
+		}
+		if _, found := overrides[pkg]; found {
+			return fmt.Errorf("duplicate log level override of package [%s]", pkg)
+		}
+		l, ok := LevelMap[o.Level]
+		if !ok {
+			return fmt.Errorf("invalid log level [%s] received for package [%s]", o.Level, pkg)
+		}
+		ttl := lv.defaultTTL
+		if o.TTL != "" {
+			var err error
+			if ttl, err = time.ParseDuration(o.TTL); err != nil || ttl <= 0 {
+				return fmt.Errorf("invalid log level override ttl [%s] received for package [%s]", o.TTL, pkg)
+			}
+		}
+		overrides[pkg] = override{level: l, expires: now.Add(ttl)}
+	}
+
+	lv.lock.Lock()
+	defer lv.lock.Unlock()
+	atomic.StoreInt32(&lv.level, int32(level))
+	lv.overrides = overrides
+	atomic.StoreInt32(&lv.active, int32(len(overrides)))
+	return nil
+}
+
+// State level and the unexpired overrides in effect
+func (lv *Levels) State() LevelState {
+	lv.prune()
+	lv.lock.RLock()
+	defer lv.lock.RUnlock()
+	state := LevelState{Level: lv.Level(), Overrides: make([]Override, 0, len(lv.overrides))}
+	for pkg, o := range lv.overrides {
+		state.Overrides = append(state.Overrides, Override{Package: pkg, Level: levelName(o.level), ExpiresAt: o.expires})
+	}
+	sort.Slice(state.Overrides, func(i, j int) bool { return state.Overrides[i].Package < state.Overrides[j].Package })
+	return state
+}
+
+// overridden check whether any override is set
+func (lv *Levels) overridden() bool {
+	return atomic.LoadInt32(&lv.active) > 0
+}
+
+// levelOf level of the package, the one of its most specific unexpired override otherwise the level of the logger
+func (lv *Levels) levelOf(pkg string) int {
+	now := time.Now()
+	expired := false
+	level, matched := int(atomic.LoadInt32(&lv.level)), -1
+
+	lv.lock.RLock()
+	for p, o := range lv.overrides {
+		if now.After(o.expires) {
+			expired = true
+			continue
+		}
+		if (pkg == p || strings.HasPrefix(pkg, p+"/")) && len(p) > matched {
+			level, matched = o.level, len(p)
+		}
+	}
+	lv.lock.RUnlock()
+
+	if expired {
+		lv.prune()
+	}
+	return level
+}
+
+// prune remove the expired overrides, reverting their packages to the level of the logger
+func (lv *Levels) prune() {
+	now := time.Now()
+	lv.lock.Lock()
+	defer lv.lock.Unlock()
+	for p, o := range lv.overrides {
+		if now.After(o.expires) {
+			delete(lv.overrides, p)
+		}
+	}
+	atomic.StoreInt32(&lv.active, int32(len(lv.overrides)))
+}
+
+// LoadLevelConfig read the levels of the json file
+func LoadLevelConfig(path string) (LevelConfig, error) {
+	var c LevelConfig
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return c, fmt.Errorf("error reading log config file [%s] due to, %w", path, err)
+	}
+	if err = json.Unmarshal(b, &c); err != nil {
+		return c, fmt.Errorf("error parsing log config file [%s] due to, %w", path, err)
+	}
+	return c, nil
+}
+
+func levelName(level int) string {
+	for name, l := range LevelMap {
+		if l == level {
+			return name
+		}
+	}
+	return ""
+}
+
+// packageOf package of the source file, its directory below `internal` otherwise the name of its directory
+func packageOf(file string) string {
+	dir := file
+	if i := strings.LastIndexByte(file, '/'); i >= 0 {
+		dir = file[:i]
+	}
+	if i := strings.LastIndex(dir, "/internal/"); i >= 0 {
+		return dir[i+len("/internal/"):]
+	}
+	if i := strings.LastIndexByte(dir, '/'); i >= 0 {
+		return dir[i+1:]
+	}
+	return dir
+}
@@ -0,0 +1,77 @@
+package log
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestLevelsApply(t *testing.T) {
+	levels, err := NewLevels(INFO, time.Minute)
+	assert.NoError(t, err)
+
+	err = levels.Apply(LevelConfig{Level: DEBUG, Overrides: []LevelOverrideConfig{
+		{Package: "adaptors/cache", Level: TRACE},
+		{Package: "/http/", Level: WARN, TTL: "1h"},
+	}})
+	assert.NoError(t, err)
+
+	state := levels.State()
+	assert.Equal(t, DEBUG, state.Level)
+	assert.Len(t, state.Overrides, 2)
+	assert.Equal(t, "adaptors/cache", state.Overrides[0].Package)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), state.Overrides[0].ExpiresAt, time.Second)
+	assert.Equal(t, "http", state.Overrides[1].Package)
+	assert.Equal(t, WARN, state.Overrides[1].Level)
+
+	assert.Equal(t, LevelMap[TRACE], levels.levelOf("adaptors/cache"))
+	assert.Equal(t, LevelMap[WARN], levels.levelOf("http/handlers"))
+	assert.Equal(t, LevelMap[DEBUG], levels.levelOf("httpx"))
+	assert.Equal(t, LevelMap[DEBUG], levels.levelOf("services"))
+
+	for _, invalid := range []LevelConfig{
+		{Level: "VERBOSE"},
+		{Level: INFO, Overrides: []LevelOverrideConfig{{Package: "services", Level: "VERBOSE"}}},
+		{Level: INFO, Overrides: []LevelOverrideConfig{{Package: "", Level: INFO}}},
+		{Level: INFO, Overrides: []LevelOverrideConfig{{Package: "services", Level: INFO, TTL: "-1m"}}},
+		{Level: INFO, Overrides: []LevelOverrideConfig{{Package: "services", Level: INFO}, {Package: "services/", Level: INFO}}},
+	} {
+		assert.Error(t, levels.Apply(invalid))
+	}
+	// invalid configurations are not applied
+	assert.Equal(t, DEBUG, levels.Level())
+	assert.Len(t, levels.State().Overrides, 2)
+}
+
+func TestLevelsOverrideExpiry(t *testing.T) {
+	levels, err := NewLevels(ERROR, time.Minute)
+	assert.NoError(t, err)
+	assert.NoError(t, levels.Apply(LevelConfig{Level: ERROR, Overrides: []LevelOverrideConfig{
+		{Package: "pkg/log", Level: TRACE, TTL: "50ms"},
+	}}))
+
+	var out bytes.Buffer
+	l, err := NewLogger(INFO, WithLevels(levels), WithOutput(&out))
+	assert.NoError(t, err)
+	l.Debug("overridden")
+	assert.Contains(t, out.String(), "overridden")
+
+	time.Sleep(60 * time.Millisecond)
+	out.Reset()
+	l.Debug("reverted")
+	assert.Empty(t, out.String())
+	assert.Empty(t, levels.State().Overrides)
+	assert.False(t, levels.overridden())
+
+	assert.NoError(t, levels.SetLevel(DEBUG))
+	l.With().Debug("runtime level")
+	assert.Contains(t, out.String(), "runtime level")
+}
+
+func TestPackageOf(t *testing.T) {
+	assert.Equal(t, "adaptors/cache", packageOf("/src/article-dispatcher/internal/adaptors/cache/cache.go"))
+	assert.Equal(t, "services", packageOf("/src/article-dispatcher/internal/services/article.go"))
+	assert.Equal(t, "bootstrap", packageOf("/go/pkg/mod/bootstrap/boot.go"))
+}
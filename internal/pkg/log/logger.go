@@ -3,7 +3,12 @@ package log
 import (
 	domLogger "article-dispatcher/internal/domain/adaptors/logger"
 	"fmt"
-	"log"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"time"
 )
 
 const (
@@ -26,58 +31,163 @@ var LevelMap = map[string]int{
 	TRACE: 1,
 }
 
+// callerDepth frames between the caller of the logger and the entry writer
+const callerDepth = 2
+
+// defaultOverrideTTL ttl of the level overrides of the loggers created without runtime levels
+const defaultOverrideTTL = 15 * time.Minute
+
 type logger struct {
-	Level string
+	levels  *Levels
+	format  string
+	service string
+	sink    Sink
+	fields  []domLogger.Field
+}
+
+// Option option of the logger
+type Option func(l *logger) error
+
+// WithFormat format of the entries, one of text, json or logfmt
+func WithFormat(format string) Option {
+	return func(l *logger) error {
+		if _, ok := formatters[format]; !ok {
+			return fmt.Errorf("invalid log format received [%s]", format)
+		}
+		l.format = format
+		return nil
+	}
+}
+
+// WithService name of the service the json and logfmt entries are annotated with
+func WithService(name string) Option {
+	return func(l *logger) error {
+		l.service = name
+		return nil
+	}
+}
+
+// WithLevels runtime levels shared with the logger, taking precedence over the level of the logger
+func WithLevels(levels *Levels) Option {
+	return func(l *logger) error {
+		l.levels = levels
+		return nil
+	}
+}
+
+// WithOutput writer of the entries, stderr by default
+func WithOutput(writer io.Writer) Option {
+	return func(l *logger) error {
+		l.sink = NewWriterSink(writer)
+		return nil
+	}
+}
+
+// WithSink sink of the entries, closed by the logger before exiting on fatal entries
+func WithSink(sink Sink) Option {
+	return func(l *logger) error {
+		l.sink = sink
+		return nil
+	}
 }
 
 // NewLogger create a new logger with several levels of logging
 // FATAL being the highest level and TRACE being the lowest
-func NewLogger(level string) (domLogger.Logger, error) {
-	_, ok := LevelMap[level]
-	if !ok {
-		return nil, fmt.Errorf("invalid log level received [%s]", level)
+func NewLogger(level string, options ...Option) (domLogger.Logger, error) {
+	l := &logger{format: FormatText, sink: NewWriterSink(os.Stderr)}
+	for _, option := range options {
+		if err := option(l); err != nil {
+			return nil, err
+		}
+	}
+	if l.levels == nil {
+		levels, err := NewLevels(level, defaultOverrideTTL)
+		if err != nil {
+			return nil, err
+		}
+		l.levels = levels
 	}
-
-	l := &logger{Level: level}
 	return l, nil
 }
 
-// Fatal only fatal logs will be logged
-func (l *logger) Fatal(message string) {
-	log.Fatalln("[FATAL]: ", message)
+// Fatal only fatal logs will be logged, the sink is flushed before exiting
+func (l *logger) Fatal(message string, fields ...domLogger.Field) {
+	l.write(FATAL, message, fields)
+	_ = l.sink.Close()
+	os.Exit(1)
 }
 
 // Error both fatal and error logs will be logged
-func (l *logger) Error(message string) {
-	if LevelMap[l.Level] <= LevelMap[ERROR] {
-		log.Println("[ERROR]: ", message)
-	}
+func (l *logger) Error(message string, fields ...domLogger.Field) {
+	l.write(ERROR, message, fields)
 }
 
 // Warn fatal,error and warn logs will be logged
-func (l *logger) Warn(message string) {
-	if LevelMap[l.Level] <= LevelMap[WARN] {
-		log.Println("[WARN]: ", message)
-	}
+func (l *logger) Warn(message string, fields ...domLogger.Field) {
+	l.write(WARN, message, fields)
 }
 
 // Debug fatal,error,warn and debug logs will be logged
-func (l *logger) Debug(message string) {
-	if LevelMap[l.Level] <= LevelMap[DEBUG] {
-		log.Println("[DEBUG]: ", message)
-	}
+func (l *logger) Debug(message string, fields ...domLogger.Field) {
+	l.write(DEBUG, message, fields)
 }
 
 // Info fatal,error,warn,debug and info logs will be logged
-func (l *logger) Info(message string) {
-	if LevelMap[l.Level] <= LevelMap[INFO] {
-		log.Println("[INFO]: ", message)
-	}
+func (l *logger) Info(message string, fields ...domLogger.Field) {
+	l.write(INFO, message, fields)
 }
 
 // Trace fatal,error,warn,debug,info and trace logs will be logged
-func (l *logger) Trace(message string) {
-	if LevelMap[l.Level] <= LevelMap[TRACE] {
-		log.Println("[TRACE]: ", message)
+func (l *logger) Trace(message string, fields ...domLogger.Field) {
+	l.write(TRACE, message, fields)
+}
+
+// With child logger sharing the level and the output, annotating its entries with the fields
+func (l *logger) With(fields ...domLogger.Field) domLogger.Logger {
+	child := *l
+	child.fields = append(append(make([]domLogger.Field, 0, len(l.fields)+len(fields)), l.fields...), fields...)
+	return &child
+}
+
+// write write the entry when the level is enabled for the package of the caller
+func (l *logger) write(level, message string, fields []domLogger.Field) {
+	enabled := LevelMap[level] >= int(atomic.LoadInt32(&l.levels.level))
+	if !enabled && !l.levels.overridden() {
+		return
+	}
+	_, file, line, ok := runtime.Caller(callerDepth)
+	if ok && l.levels.overridden() {
+		enabled = LevelMap[level] >= l.levels.levelOf(packageOf(file))
+	}
+	if !enabled {
+		return
+	}
+
+	e := entry{
+		Time:    time.Now(),
+		Level:   level,
+		Service: l.service,
+		Message: message,
+		Fields:  l.fields,
+	}
+	if len(fields) > 0 {
+		e.Fields = append(append(make([]domLogger.Field, 0, len(l.fields)+len(fields)), l.fields...), fields...)
+	}
+	if ok {
+		e.Caller = fmt.Sprintf("%s:%d", shortPath(file), line)
+	}
+
+	if err := l.sink.Write(level, formatters[l.format](e)); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "failed to write log entry due to, %s\n", err)
+	}
+}
+
+// shortPath package directory and file name of the path
+func shortPath(path string) string {
+	if i := strings.LastIndexByte(path, '/'); i > 0 {
+		if j := strings.LastIndexByte(path[:i], '/'); j >= 0 {
+			return path[j+1:]
+		}
 	}
+	return path
 }
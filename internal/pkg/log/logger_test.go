@@ -0,0 +1,71 @@
+package log
+
+import (
+	domLogger "article-dispatcher/internal/domain/adaptors/logger"
+
+	"github.com/stretchr/testify/assert"
+
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNewLogger(t *testing.T) {
+	_, err := NewLogger("VERBOSE")
+	assert.Error(t, err)
+	_, err = NewLogger(INFO, WithFormat("xml"))
+	assert.Error(t, err)
+}
+
+func TestJSONFormat(t *testing.T) {
+	var out bytes.Buffer
+	l, err := NewLogger(DEBUG, WithFormat(FormatJSON), WithService("article-dispatcher"), WithOutput(&out))
+	assert.NoError(t, err)
+
+	child := l.With(domLogger.F("trace_id", "abc"))
+	child.Error("article service, get article error", domLogger.F("error", errors.New("not found")),
+		domLogger.F("count", 2), domLogger.F("msg", "clash"))
+	child.Trace("below the level")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Len(t, lines, 1)
+	var e map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(lines[0]), &e))
+	assert.Equal(t, "error", e["level"])
+	assert.Equal(t, "article-dispatcher", e["service"])
+	assert.Equal(t, "article service, get article error", e["msg"])
+	assert.Equal(t, "abc", e["trace_id"])
+	assert.Equal(t, "not found", e["error"])
+	assert.Equal(t, float64(2), e["count"])
+	assert.Equal(t, "clash", e["field.msg"])
+	assert.Regexp(t, `^log/logger_test\.go:\d+$`, e["caller"])
+	assert.NotEmpty(t, e["ts"])
+	assert.True(t, strings.HasPrefix(lines[0], `{"ts":`))
+
+	// the parent is not annotated with the fields of the child
+	out.Reset()
+	l.Info("parent")
+	assert.NotContains(t, out.String(), "trace_id")
+}
+
+func TestLogfmtFormat(t *testing.T) {
+	var out bytes.Buffer
+	l, err := NewLogger(INFO, WithFormat(FormatLogfmt), WithService("svc"), WithOutput(&out))
+	assert.NoError(t, err)
+
+	l.Info("server starting", domLogger.F("port", "8888"), domLogger.F("path", "/a b"), domLogger.F("empty", ""))
+	line := out.String()
+	assert.Regexp(t, `^ts=\S+ level=info service=svc caller=log/logger_test\.go:\d+ msg="server starting" `+
+		`port=8888 path="/a b" empty=""\n$`, line)
+}
+
+func TestTextFormat(t *testing.T) {
+	var out bytes.Buffer
+	l, err := NewLogger(INFO, WithOutput(&out))
+	assert.NoError(t, err)
+
+	l.Warn("dropped spans", domLogger.F("spans", 3))
+	assert.Regexp(t, `^\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2} \[WARN\]:  dropped spans spans=3\n$`, out.String())
+}
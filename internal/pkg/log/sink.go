@@ -0,0 +1,169 @@
+package log
+
+import (
+	domLogger "article-dispatcher/internal/domain/adaptors/logger"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Sink destination of the formatted log entries
+// Write - write the entry logged at the level
+// Close - flush the buffered entries and release the sink, entries written afterwards are dropped
+type Sink interface {
+	Write(level string, entry []byte) error
+	Close() error
+}
+
+// WriterSink sink writing the entries to a writer, e.g. stdout or stderr
+type WriterSink struct {
+	lock   sync.Mutex
+	writer io.Writer
+}
+
+func NewWriterSink(writer io.Writer) *WriterSink {
+	return &WriterSink{writer: writer}
+}
+
+func (ws *WriterSink) Write(_ string, entry []byte) error {
+	ws.lock.Lock()
+	defer ws.lock.Unlock()
+	_, err := ws.writer.Write(entry)
+	return err
+}
+
+// Close the standard streams are left open
+func (ws *WriterSink) Close() error {
+	return nil
+}
+
+// LevelSink sink receiving the entries logged at its minimum level and above
+type LevelSink struct {
+	Sink  Sink
+	Level string
+}
+
+// FanoutSink sink writing every entry to the sinks whose minimum level it reaches
+type FanoutSink []LevelSink
+
+func (fs FanoutSink) Write(level string, entry []byte) error {
+	var failed error
+	for _, s := range fs {
+		if LevelMap[level] < LevelMap[s.Level] {
+			continue
+		}
+		if err := s.Sink.Write(level, entry); err != nil && failed == nil {
+			failed = err
+		}
+	}
+	return failed
+}
+
+func (fs FanoutSink) Close() error {
+	var failed error
+	for _, s := range fs {
+		if err := s.Sink.Close(); err != nil && failed == nil {
+			failed = err
+		}
+	}
+	return failed
+}
+
+// AsyncSink sink buffering the entries to write them in the background, so that logging never blocks the caller.
+// Entries are dropped while the buffer is full, and the number of dropped entries is logged in the format of the
+// entries once it drains
+type AsyncSink struct {
+	sink    Sink
+	format  string
+	service string
+	entries chan asyncEntry
+	lock    sync.RWMutex
+	closed  bool
+	done    chan struct{}
+	dropped int64
+}
+
+type asyncEntry struct {
+	level string
+	entry []byte
+}
+
+func NewAsyncSink(sink Sink, size int, format, service string) *AsyncSink {
+	as := &AsyncSink{
+		sink:    sink,
+		format:  format,
+		service: service,
+		entries: make(chan asyncEntry, size),
+		done:    make(chan struct{}),
+	}
+	go as.run()
+	return as
+}
+
+func (as *AsyncSink) Write(level string, entry []byte) error {
+	as.lock.RLock()
+	defer as.lock.RUnlock()
+	if as.closed {
+		atomic.AddInt64(&as.dropped, 1)
+		return nil
+	}
+	select {
+	case as.entries <- asyncEntry{level: level, entry: entry}:
+	default:
+		atomic.AddInt64(&as.dropped, 1)
+	}
+	return nil
+}
+
+// Close write the buffered entries and close the sink
+func (as *AsyncSink) Close() error {
+	as.lock.Lock()
+	if as.closed {
+		as.lock.Unlock()
+		<-as.done
+		return nil
+	}
+	as.closed = true
+	close(as.entries)
+	as.lock.Unlock()
+
+	<-as.done
+	return as.sink.Close()
+}
+
+func (as *AsyncSink) run() {
+	defer close(as.done)
+	for e := range as.entries {
+		as.write(e.level, e.entry)
+		// the entries are dropped after the buffered ones, they are reported once the buffer drains
+		if len(as.entries) == 0 {
+			as.reportDropped()
+		}
+	}
+	as.reportDropped()
+}
+
+// reportDropped write the number of entries dropped since the last report
+func (as *AsyncSink) reportDropped() {
+	dropped := atomic.SwapInt64(&as.dropped, 0)
+	if dropped == 0 {
+		return
+	}
+	as.write(WARN, formatters[as.format](entry{
+		Time:    time.Now(),
+		Level:   WARN,
+		Service: as.service,
+		Message: "log buffer full, log entries dropped",
+		Fields:  []domLogger.Field{domLogger.F("dropped", dropped)},
+	}))
+}
+
+// write write the entry to the sink, failures are reported on stderr as they cannot be logged
+func (as *AsyncSink) write(level string, b []byte) {
+	if err := as.sink.Write(level, b); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "failed to write log entry due to, %s\n", err)
+	}
+}
@@ -0,0 +1,196 @@
+package log
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memorySink sink keeping the entries in memory, blocking the writes until it is released when gated
+type memorySink struct {
+	lock    sync.Mutex
+	entries []string
+	gate    chan struct{}
+	closed  bool
+}
+
+func (ms *memorySink) Write(_ string, entry []byte) error {
+	if ms.gate != nil {
+		<-ms.gate
+	}
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+	ms.entries = append(ms.entries, string(entry))
+	return nil
+}
+
+func (ms *memorySink) Close() error {
+	ms.closed = true
+	return nil
+}
+
+func TestFanoutSink(t *testing.T) {
+	all, errors := &memorySink{}, &memorySink{}
+	fanout := FanoutSink{{Sink: all, Level: TRACE}, {Sink: errors, Level: ERROR}}
+
+	assert.NoError(t, fanout.Write(DEBUG, []byte("debug")))
+	assert.NoError(t, fanout.Write(ERROR, []byte("error")))
+	assert.NoError(t, fanout.Write(FATAL, []byte("fatal")))
+	assert.Equal(t, []string{"debug", "error", "fatal"}, all.entries)
+	assert.Equal(t, []string{"error", "fatal"}, errors.entries)
+
+	assert.NoError(t, fanout.Close())
+	assert.True(t, all.closed)
+	assert.True(t, errors.closed)
+}
+
+func TestAsyncSink(t *testing.T) {
+	inner := &memorySink{gate: make(chan struct{})}
+	async := NewAsyncSink(inner, 2, FormatJSON, "svc")
+
+	// the background writer blocks on the sink, once the buffer is full the entries are dropped without blocking
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 10; i++ {
+			assert.NoError(t, async.Write(INFO, []byte(fmt.Sprintf("entry %d\n", i))))
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("writes blocked on a full buffer")
+	}
+
+	// closing flushes the buffered entries along with the number of dropped entries
+	close(inner.gate)
+	assert.NoError(t, async.Close())
+	assert.True(t, inner.closed)
+	assert.Equal(t, "entry 0\n", inner.entries[0])
+	assert.GreaterOrEqual(t, len(inner.entries), 3)
+	notices := 0
+	for _, e := range inner.entries {
+		if strings.Contains(e, `"msg":"log buffer full, log entries dropped","dropped":`) {
+			notices++
+		}
+	}
+	assert.Equal(t, 1, notices)
+
+	assert.NoError(t, async.Write(INFO, []byte("after close\n")))
+	assert.NoError(t, async.Close())
+	assert.NotContains(t, inner.entries, "after close\n")
+}
+
+func TestFileSink(t *testing.T) {
+	dir, err := os.MkdirTemp("", "file-sink")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "logs", "app.log")
+
+	sink, err := NewFileSink(path, 20, 0, 2, true)
+	assert.NoError(t, err)
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, sink.Write(INFO, []byte(fmt.Sprintf("entry number %d\n", i))))
+		// rotated files are named by the millisecond of their rotation
+		time.Sleep(2 * time.Millisecond)
+	}
+	assert.NoError(t, sink.Close())
+	assert.Error(t, sink.Write(INFO, []byte("closed\n")))
+
+	current, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "entry number 3\n", string(current))
+
+	// three rotations, of which the oldest backup is removed
+	backups, err := filepath.Glob(path + ".*")
+	assert.NoError(t, err)
+	assert.Len(t, backups, 2)
+	for i, backup := range backups {
+		assert.True(t, strings.HasSuffix(backup, ".gz"))
+		f, err := os.Open(backup)
+		assert.NoError(t, err)
+		zr, err := gzip.NewReader(f)
+		assert.NoError(t, err)
+		b, err := io.ReadAll(zr)
+		assert.NoError(t, err)
+		_ = f.Close()
+		assert.Equal(t, fmt.Sprintf("entry number %d\n", i+1), string(b))
+	}
+}
+
+func TestFileSinkAge(t *testing.T) {
+	dir, err := os.MkdirTemp("", "file-sink")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "app.log")
+
+	sink, err := NewFileSink(path, 0, 20*time.Millisecond, 0, false)
+	assert.NoError(t, err)
+	assert.NoError(t, sink.Write(INFO, []byte("old\n")))
+	assert.NoError(t, sink.Write(INFO, []byte("old\n")))
+	time.Sleep(30 * time.Millisecond)
+	assert.NoError(t, sink.Write(INFO, []byte("new\n")))
+	assert.NoError(t, sink.Close())
+
+	backups, err := filepath.Glob(path + ".*")
+	assert.NoError(t, err)
+	assert.Len(t, backups, 1)
+	b, err := os.ReadFile(backups[0])
+	assert.NoError(t, err)
+	assert.Equal(t, "old\nold\n", string(b))
+}
+
+func TestSyslogSink(t *testing.T) {
+	dir, err := os.MkdirTemp("", "syslog-sink")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	socket := filepath.Join(dir, "log.sock")
+
+	// local stand-in of the syslog daemon
+	conn, err := net.ListenPacket("unixgram", socket)
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	sink, err := NewSyslogSink(socket, "article-dispatcher")
+	assert.NoError(t, err)
+	assert.NoError(t, sink.Write(ERROR, []byte("level=error msg=failed\n")))
+	assert.NoError(t, sink.Write(DEBUG, []byte("level=debug msg=details\n")))
+	assert.NoError(t, sink.Close())
+
+	buf := make([]byte, 1024)
+	for _, expected := range []string{"<27>", "<31>"} {
+		assert.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+		n, _, err := conn.ReadFrom(buf)
+		assert.NoError(t, err)
+		message := string(buf[:n])
+		// daemon facility with the error and debug severities
+		assert.True(t, strings.HasPrefix(message, expected), message)
+		assert.Contains(t, message, "article-dispatcher[")
+		assert.False(t, bytes.HasSuffix(buf[:n], []byte("\n\n")))
+	}
+
+	_, err = NewSyslogSink(filepath.Join(dir, "missing.sock"), "article-dispatcher")
+	assert.Error(t, err)
+}
+
+func TestConfigSinks(t *testing.T) {
+	c := LoggerConfig{Sinks: []string{"stdout:INFO", "stderr"}}
+	entries, err := c.sinks()
+	assert.NoError(t, err)
+	assert.Equal(t, []sinkEntry{{name: SinkStdout, level: INFO}, {name: SinkStderr, level: TRACE}}, entries)
+
+	for _, invalid := range [][]string{{}, {"kafka"}, {"stdout:LOUD"}, {"stdout", "stdout:ERROR"}} {
+		_, err = LoggerConfig{Sinks: invalid}.sinks()
+		assert.Error(t, err, invalid)
+	}
+}
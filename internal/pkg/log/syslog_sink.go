@@ -0,0 +1,44 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"log/syslog"
+)
+
+// SyslogSink sink sending the entries to the local syslog daemon over its unix socket, with the severity of their level
+type SyslogSink struct {
+	writer *syslog.Writer
+}
+
+// NewSyslogSink sink of the syslog daemon listening on the unix socket, e.g. /dev/log, the entries are tagged with tag
+func NewSyslogSink(socket, tag string) (*SyslogSink, error) {
+	var err error
+	for _, network := range []string{"unixgram", "unix"} {
+		var writer *syslog.Writer
+		if writer, err = syslog.Dial(network, socket, syslog.LOG_DAEMON|syslog.LOG_INFO, tag); err == nil {
+			return &SyslogSink{writer: writer}, nil
+		}
+	}
+	return nil, fmt.Errorf("error connecting to syslog socket [%s] due to, %w", socket, err)
+}
+
+func (ss *SyslogSink) Write(level string, entry []byte) error {
+	message := string(bytes.TrimRight(entry, "\n"))
+	switch level {
+	case FATAL:
+		return ss.writer.Crit(message)
+	case ERROR:
+		return ss.writer.Err(message)
+	case WARN:
+		return ss.writer.Warning(message)
+	case INFO:
+		return ss.writer.Info(message)
+	default:
+		return ss.writer.Debug(message)
+	}
+}
+
+func (ss *SyslogSink) Close() error {
+	return ss.writer.Close()
+}
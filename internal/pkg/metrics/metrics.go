@@ -19,7 +19,10 @@ type RouterMetrics struct {
 	Logger logger.Logger
 }
 
-var RequestLatency *prometheus.SummaryVec
+var (
+	RequestLatency      *prometheus.SummaryVec
+	RateLimitRejections *prometheus.CounterVec
+)
 
 // InitMetrics init server and metrics reports
 func (rm *RouterMetrics) InitMetrics() error {
@@ -37,13 +40,20 @@ func (rm *RouterMetrics) InitMetrics() error {
 		Help:      "http_request_latency",
 	}, []string{"endpoint", "error"})
 
-	prometheus.MustRegister(RequestLatency)
+	RateLimitRejections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: rm.Conf.System,
+		Subsystem: rm.Conf.SubSystem,
+		Name:      "rate_limit_rejections_total",
+		Help:      "requests rejected by the rate limiter per route",
+	}, []string{"route"})
+
+	prometheus.MustRegister(RequestLatency, RateLimitRejections)
 
 	return nil
 }
 
 func (rm *RouterMetrics) Start() error {
-	rm.Logger.Info(fmt.Sprintf(`metrics server starting on port :%s`, rm.Conf.HTTP.Host))
+	rm.Logger.Info("metrics server starting", logger.F("port", rm.Conf.HTTP.Host))
 	if err := rm.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		return err
 	}
@@ -54,6 +64,6 @@ func (rm *RouterMetrics) Start() error {
 func (rm *RouterMetrics) Stop() error {
 	c, fn := context.WithTimeout(context.Background(), rm.Conf.HTTP.ShutdownWait)
 	defer fn()
-	rm.Logger.Info(fmt.Sprintf(`metrics server shutting down on port %s`, rm.Conf.HTTP.Host))
+	rm.Logger.Info("metrics server shutting down", logger.F("port", rm.Conf.HTTP.Host))
 	return rm.server.Shutdown(c)
 }
@@ -0,0 +1,118 @@
+package tracing
+
+import (
+	"github.com/caarlos0/env/v6"
+	"github.com/pkg/errors"
+
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// exporters
+const (
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+var Conf TracingConfig
+
+type TracingConfig struct {
+	Enabled     bool   `env:"TRACING_ENABLED" envDefault:"false"`
+	Exporter    string `env:"TRACING_EXPORTER" envDefault:"stdout"`
+	ServiceName string `env:"TRACING_SERVICE_NAME" envDefault:"article-dispatcher"`
+	OTLP        struct {
+		Endpoint string `env:"TRACING_OTLP_ENDPOINT" envDefault:"http://localhost:4318/v1/traces"`
+		// Headers `key=value` headers of the export requests, e.g. the collector credentials
+		Headers []string      `env:"TRACING_OTLP_HEADERS"`
+		Timeout time.Duration `env:"TRACING_OTLP_TIMEOUT" envDefault:"10s"`
+	}
+	Batch struct {
+		Size      int           `env:"TRACING_BATCH_SIZE" envDefault:"512"`
+		QueueSize int           `env:"TRACING_QUEUE_SIZE" envDefault:"2048"`
+		Interval  time.Duration `env:"TRACING_BATCH_INTERVAL" envDefault:"5s"`
+	}
+}
+
+// Register tracing configurations
+func (c *TracingConfig) Register() error {
+	err := env.Parse(&Conf)
+	if err != nil {
+		return errors.Wrap(err, "register failed, error loading tracing config")
+	}
+	return nil
+}
+
+// Validate tracing configurations
+func (c *TracingConfig) Validate() error {
+	if !Conf.Enabled {
+		return nil
+	}
+	if Conf.Exporter != ExporterStdout && Conf.Exporter != ExporterOTLP {
+		return errors.Errorf("TRACING_EXPORTER [%s] must be one of %s or %s", Conf.Exporter, ExporterStdout, ExporterOTLP)
+	}
+	if Conf.Exporter == ExporterOTLP && Conf.OTLP.Endpoint == "" {
+		return errors.New("TRACING_OTLP_ENDPOINT cannot be empty")
+	}
+	if _, err := Conf.headers(); err != nil {
+		return err
+	}
+	if Conf.Batch.Size <= 0 || Conf.Batch.QueueSize <= 0 || Conf.Batch.Interval <= 0 {
+		return errors.New("TRACING_BATCH_SIZE, TRACING_QUEUE_SIZE and TRACING_BATCH_INTERVAL must be positive")
+	}
+	return nil
+}
+
+// Print tracing configurations, masking the export headers which can hold credentials
+func (c *TracingConfig) Print() interface{} {
+	defer log.Println("---loading tracing configs---")
+	masked := Conf
+	masked.OTLP.Headers = make([]string, len(Conf.OTLP.Headers))
+	for i, header := range Conf.OTLP.Headers {
+		masked.OTLP.Headers[i] = strings.SplitN(header, "=", 2)[0] + "=*****"
+	}
+	return &masked
+}
+
+// Options batching options of the configuration
+func (c TracingConfig) Options() Options {
+	return Options{
+		BatchSize: c.Batch.Size,
+		QueueSize: c.Batch.QueueSize,
+		Interval:  c.Batch.Interval,
+		Timeout:   c.OTLP.Timeout,
+	}
+}
+
+// NewExporter exporter of the configuration, the stdout exporter writes to out
+func (c TracingConfig) NewExporter(out io.Writer) (Exporter, error) {
+	if c.Exporter == ExporterStdout {
+		return StdoutExporter{Writer: out}, nil
+	}
+	headers, err := c.headers()
+	if err != nil {
+		return nil, err
+	}
+	return OTLPExporter{
+		Endpoint:    c.OTLP.Endpoint,
+		ServiceName: c.ServiceName,
+		Headers:     headers,
+		Client:      &http.Client{Timeout: c.OTLP.Timeout},
+	}, nil
+}
+
+// headers parse the `key=value` export headers
+func (c TracingConfig) headers() (map[string]string, error) {
+	headers := make(map[string]string, len(c.OTLP.Headers))
+	for _, header := range c.OTLP.Headers {
+		kv := strings.SplitN(header, "=", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+			return nil, errors.Errorf("TRACING_OTLP_HEADERS entry [%s] must be in the key=value format",
+				strings.SplitN(header, "=", 2)[0])
+		}
+		headers[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+	}
+	return headers, nil
+}
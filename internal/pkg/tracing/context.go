@@ -0,0 +1,134 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	HeaderRequestID   = "X-Request-ID"
+	HeaderTraceParent = "traceparent"
+	HeaderTraceState  = "tracestate"
+
+	// maxTraceStateLength maximum length of the propagated tracestate header
+	maxTraceStateLength = 512
+)
+
+var (
+	requestIDPattern   = regexp.MustCompile(`^[A-Za-z0-9._:/+=-]{1,128}$`)
+	traceParentPattern = regexp.MustCompile(`^([0-9a-f]{2})-([0-9a-f]{32})-([0-9a-f]{16})-([0-9a-f]{2})(-.*)?$`)
+
+	// ErrInvalidTraceParent traceparent header is not a valid W3C trace context
+	ErrInvalidTraceParent = errors.New("invalid traceparent")
+)
+
+type contextKey int
+
+const (
+	requestIDKey contextKey = iota
+	traceContextKey
+)
+
+// TraceContext W3C trace context of the request, span id is the id of the span of this service
+type TraceContext struct {
+	TraceID  string
+	ParentID string
+	SpanID   string
+	Flags    string
+	State    string
+}
+
+// TraceParent traceparent header value of the trace context propagating the span of this service
+func (tc TraceContext) TraceParent() string {
+	return fmt.Sprintf("00-%s-%s-%s", tc.TraceID, tc.SpanID, tc.Flags)
+}
+
+// Sampled check whether the sampled flag of the trace context is set
+func (tc TraceContext) Sampled() bool {
+	b, err := hex.DecodeString(tc.Flags)
+	return err == nil && len(b) == 1 && b[0]&0x01 == 1
+}
+
+// ParseTraceParent parse the traceparent header, versions above 00 are parsed by their 00 prefix
+func ParseTraceParent(header string) (TraceContext, error) {
+	m := traceParentPattern.FindStringSubmatch(strings.TrimSpace(header))
+	if m == nil || m[1] == "ff" || (m[1] == "00" && m[5] != "") {
+		return TraceContext{}, ErrInvalidTraceParent
+	}
+	if isZero(m[2]) || isZero(m[3]) {
+		return TraceContext{}, ErrInvalidTraceParent
+	}
+	return TraceContext{TraceID: m[2], ParentID: m[3], Flags: m[4]}, nil
+}
+
+// ValidRequestID check whether the request id is 1 to 128 characters of letters, digits and `._:/+=-`
+func ValidRequestID(id string) bool {
+	return requestIDPattern.MatchString(id)
+}
+
+// ValidTraceState check whether the tracestate header can be propagated
+func ValidTraceState(state string) bool {
+	if len(state) > maxTraceStateLength {
+		return false
+	}
+	for _, r := range state {
+		if r < 0x20 || r > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// NewTraceID random 16 byte trace id
+func NewTraceID() string {
+	return randomHex(16)
+}
+
+// NewSpanID random 8 byte span id
+func NewSpanID() string {
+	return randomHex(8)
+}
+
+// WithRequestID context carrying the request id
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+// RequestID request id of the context
+func RequestID(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey).(string)
+	return id, ok
+}
+
+// WithTraceContext context carrying the trace context
+func WithTraceContext(ctx context.Context, tc TraceContext) context.Context {
+	return context.WithValue(ctx, traceContextKey, tc)
+}
+
+// FromContext trace context of the context
+func FromContext(ctx context.Context) (TraceContext, bool) {
+	tc, ok := ctx.Value(traceContextKey).(TraceContext)
+	return tc, ok
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	for {
+		if _, err := rand.Read(b); err != nil {
+			panic(fmt.Sprintf("error generating random id due to, %s", err))
+		}
+		if id := hex.EncodeToString(b); !isZero(id) {
+			return id
+		}
+	}
+}
+
+// isZero check whether the hex id is all zeros, which is an invalid id
+func isZero(id string) bool {
+	return strings.Trim(id, "0") == ""
+}
@@ -0,0 +1,58 @@
+package tracing
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	"context"
+	"testing"
+)
+
+func TestParseTraceParent(t *testing.T) {
+	tc, err := ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	assert.NoError(t, err)
+	assert.Equal(t, TraceContext{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", ParentID: "00f067aa0ba902b7", Flags: "01"}, tc)
+	assert.True(t, tc.Sampled())
+
+	// future versions can carry more fields
+	_, err = ParseTraceParent("01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00-extra")
+	assert.NoError(t, err)
+
+	for _, invalid := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e473-00f067aa0ba902b7-01",
+	} {
+		_, err = ParseTraceParent(invalid)
+		assert.ErrorIs(t, err, ErrInvalidTraceParent, invalid)
+	}
+}
+
+func TestValidRequestID(t *testing.T) {
+	assert.True(t, ValidRequestID("f0b1c2d3-1234-4abc-9def-0123456789ab"))
+	assert.True(t, ValidRequestID("gateway:req/42"))
+	assert.False(t, ValidRequestID(""))
+	assert.False(t, ValidRequestID("id with spaces"))
+	assert.False(t, ValidRequestID("id\nforged log line"))
+}
+
+func TestContext(t *testing.T) {
+	ctx := context.Background()
+	_, ok := RequestID(ctx)
+	assert.False(t, ok)
+	_, ok = FromContext(ctx)
+	assert.False(t, ok)
+
+	tc := TraceContext{TraceID: NewTraceID(), SpanID: NewSpanID(), Flags: "00"}
+	ctx = WithTraceContext(WithRequestID(ctx, "id"), tc)
+	id, _ := RequestID(ctx)
+	assert.Equal(t, "id", id)
+	got, _ := FromContext(ctx)
+	assert.Equal(t, tc, got)
+	assert.Len(t, got.TraceID, 32)
+	assert.Len(t, got.SpanID, 16)
+	assert.Equal(t, "00-"+tc.TraceID+"-"+tc.SpanID+"-00", got.TraceParent())
+}
@@ -0,0 +1,226 @@
+package tracing
+
+import (
+	"article-dispatcher/internal/domain/adaptors/logger"
+	"article-dispatcher/internal/domain/adaptors/tracer"
+	domErrors "article-dispatcher/internal/domain/errors"
+
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SpanKind role of the span in the trace
+type SpanKind int
+
+// span kinds as numbered by OTLP
+const (
+	SpanKindInternal SpanKind = 1
+	SpanKindServer   SpanKind = 2
+)
+
+// Attribute key and value annotating a span
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// SpanData ended span handed to the exporters
+type SpanData struct {
+	Name       string
+	Kind       SpanKind
+	TraceID    string
+	SpanID     string
+	ParentID   string
+	Start      time.Time
+	End        time.Time
+	Attributes []Attribute
+	Failed     bool
+	Message    string
+}
+
+// Exporter send the ended spans to a tracing backend
+type Exporter interface {
+	Export(ctx context.Context, spans []SpanData) error
+}
+
+// Options batching of the exported spans
+type Options struct {
+	// BatchSize spans exported in one call
+	BatchSize int
+	// QueueSize ended spans waiting to be exported, spans are dropped when the queue is full
+	QueueSize int
+	// Interval maximum time an ended span waits to be exported
+	Interval time.Duration
+	// Timeout of a single export
+	Timeout time.Duration
+}
+
+// Tracer records spans and exports them in batches in the background
+type Tracer struct {
+	log      logger.Logger
+	exporter Exporter
+	opts     Options
+	queue    chan SpanData
+	done     chan struct{}
+	stopped  chan struct{}
+	stop     sync.Once
+	dropped  int64
+}
+
+type spanKey struct{}
+
+func NewTracer(l logger.Logger, exporter Exporter, opts Options) *Tracer {
+	t := &Tracer{
+		log:      l,
+		exporter: exporter,
+		opts:     opts,
+		queue:    make(chan SpanData, opts.QueueSize),
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+	go t.run()
+	return t
+}
+
+// Start start a span, child of the span of the context, otherwise the server span of the trace context of the request,
+// otherwise the root of a new trace
+func (t *Tracer) Start(ctx context.Context, name string) (context.Context, tracer.Span) {
+	data := SpanData{Name: name, Kind: SpanKindInternal, Start: time.Now()}
+	if parent, ok := ctx.Value(spanKey{}).(*span); ok {
+		data.TraceID, data.ParentID, data.SpanID = parent.data.TraceID, parent.data.SpanID, NewSpanID()
+	} else if tc, ok := FromContext(ctx); ok {
+		data.Kind, data.TraceID, data.ParentID, data.SpanID = SpanKindServer, tc.TraceID, tc.ParentID, tc.SpanID
+	} else {
+		data.TraceID, data.SpanID = NewTraceID(), NewSpanID()
+	}
+	s := &span{tracer: t, data: data}
+	return context.WithValue(ctx, spanKey{}, s), s
+}
+
+// Shutdown export the queued spans and stop the tracer
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	t.stop.Do(func() { close(t.done) })
+	select {
+	case <-t.stopped:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("tracer shutdown interrupted, %w", ctx.Err())
+	}
+}
+
+func (t *Tracer) enqueue(data SpanData) {
+	select {
+	case t.queue <- data:
+	default:
+		atomic.AddInt64(&t.dropped, 1)
+	}
+}
+
+func (t *Tracer) run() {
+	defer close(t.stopped)
+	ticker := time.NewTicker(t.opts.Interval)
+	defer ticker.Stop()
+
+	batch := make([]SpanData, 0, t.opts.BatchSize)
+	for {
+		select {
+		case data := <-t.queue:
+			batch = append(batch, data)
+			if len(batch) >= t.opts.BatchSize {
+				batch = t.export(batch)
+			}
+		case <-ticker.C:
+			batch = t.export(batch)
+		case <-t.done:
+			for {
+				select {
+				case data := <-t.queue:
+					batch = append(batch, data)
+					if len(batch) >= t.opts.BatchSize {
+						batch = t.export(batch)
+					}
+				default:
+					t.export(batch)
+					return
+				}
+			}
+		}
+	}
+}
+
+// export export the batch, returning it emptied for reuse
+func (t *Tracer) export(batch []SpanData) []SpanData {
+	if dropped := atomic.SwapInt64(&t.dropped, 0); dropped > 0 {
+		t.log.Warn("dropped spans, the export queue is full", logger.F("spans", dropped))
+	}
+	if len(batch) == 0 {
+		return batch
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), t.opts.Timeout)
+	defer cancel()
+	if err := t.exporter.Export(ctx, batch); err != nil {
+		t.log.Error("failed to export spans", logger.F("spans", len(batch)), logger.F("error", err))
+	}
+	return batch[:0]
+}
+
+type span struct {
+	tracer *Tracer
+	lock   sync.Mutex
+	data   SpanData
+	ended  bool
+}
+
+func (s *span) SetAttribute(key string, value interface{}) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for i := range s.data.Attributes {
+		if s.data.Attributes[i].Key == key {
+			s.data.Attributes[i].Value = value
+			return
+		}
+	}
+	s.data.Attributes = append(s.data.Attributes, Attribute{Key: key, Value: value})
+}
+
+func (s *span) RecordError(err error) {
+	if err == nil {
+		return
+	}
+	if kind := domErrors.KindOf(err); kind != nil {
+		s.SetAttribute(tracer.AttributeErrorKind, kind.Error())
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.data.Failed, s.data.Message = true, err.Error()
+}
+
+func (s *span) End() {
+	s.lock.Lock()
+	if s.ended {
+		s.lock.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.End = time.Now()
+	data := s.data
+	data.Attributes = append([]Attribute(nil), s.data.Attributes...)
+	s.lock.Unlock()
+	s.tracer.enqueue(data)
+}
+
+// NoopTracer tracer discarding the spans, used when tracing is disabled
+type NoopTracer struct{}
+
+func (NoopTracer) Start(ctx context.Context, _ string) (context.Context, tracer.Span) {
+	return ctx, noopSpan{}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttribute(string, interface{}) {}
+func (noopSpan) RecordError(error)                {}
+func (noopSpan) End()                             {}
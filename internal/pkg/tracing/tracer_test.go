@@ -0,0 +1,152 @@
+package tracing
+
+import (
+	"article-dispatcher/internal/domain/adaptors/tracer"
+	domErrors "article-dispatcher/internal/domain/errors"
+	"article-dispatcher/internal/pkg/log"
+
+	"github.com/stretchr/testify/assert"
+
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryExporter exporter collecting the spans in memory
+type memoryExporter struct {
+	lock  sync.Mutex
+	spans []SpanData
+}
+
+func (e *memoryExporter) Export(_ context.Context, spans []SpanData) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func newTestTracer(t *testing.T, exporter Exporter) *Tracer {
+	l, err := log.NewLogger(log.ERROR)
+	assert.NoError(t, err)
+	return NewTracer(l, exporter, Options{BatchSize: 10, QueueSize: 100, Interval: time.Hour, Timeout: time.Second})
+}
+
+func TestTracer(t *testing.T) {
+	exporter := &memoryExporter{}
+	tr := newTestTracer(t, exporter)
+
+	tc := TraceContext{TraceID: NewTraceID(), ParentID: NewSpanID(), SpanID: NewSpanID(), Flags: "01"}
+	ctx, root := tr.Start(WithTraceContext(context.Background(), tc), "GET /articles/{id}")
+	childCtx, child := tr.Start(ctx, "ArticleService.Get")
+	_, grandChild := tr.Start(childCtx, "Repository.Get")
+	grandChild.SetAttribute(tracer.AttributeArticleID, "1")
+	grandChild.RecordError(fmt.Errorf("no article found, %w", domErrors.ErrNotFound))
+	grandChild.End()
+	grandChild.End()
+	child.End()
+	root.End()
+
+	assert.NoError(t, tr.Shutdown(context.Background()))
+	assert.Len(t, exporter.spans, 3)
+	repo, service, server := exporter.spans[0], exporter.spans[1], exporter.spans[2]
+
+	assert.Equal(t, SpanKindServer, server.Kind)
+	assert.Equal(t, tc.TraceID, server.TraceID)
+	assert.Equal(t, tc.SpanID, server.SpanID)
+	assert.Equal(t, tc.ParentID, server.ParentID)
+
+	assert.Equal(t, SpanKindInternal, service.Kind)
+	assert.Equal(t, tc.TraceID, service.TraceID)
+	assert.Equal(t, server.SpanID, service.ParentID)
+	assert.Equal(t, service.SpanID, repo.ParentID)
+
+	assert.True(t, repo.Failed)
+	assert.Equal(t, []Attribute{
+		{Key: tracer.AttributeArticleID, Value: "1"},
+		{Key: tracer.AttributeErrorKind, Value: "not found"},
+	}, repo.Attributes)
+	assert.False(t, service.Failed)
+}
+
+func TestTracerNewTrace(t *testing.T) {
+	exporter := &memoryExporter{}
+	tr := newTestTracer(t, exporter)
+	_, span := tr.Start(context.Background(), "job")
+	span.End()
+
+	assert.NoError(t, tr.Shutdown(context.Background()))
+	assert.Len(t, exporter.spans, 1)
+	assert.Len(t, exporter.spans[0].TraceID, 32)
+	assert.Empty(t, exporter.spans[0].ParentID)
+}
+
+func TestOTLPExporter(t *testing.T) {
+	var received otlpRequest
+	var header string
+	collector := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		header = request.Header.Get("Authorization")
+		body, _ := io.ReadAll(request.Body)
+		assert.NoError(t, json.Unmarshal(body, &received))
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer collector.Close()
+
+	start := time.Unix(1700000000, 0)
+	exporter := OTLPExporter{Endpoint: collector.URL, ServiceName: "article-dispatcher",
+		Headers: map[string]string{"Authorization": "Bearer token"}}
+	err := exporter.Export(context.Background(), []SpanData{{
+		Name: "Repository.Filter", Kind: SpanKindInternal, TraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+		SpanID: "00f067aa0ba902b7", ParentID: "53995c3f42cd8ad8", Start: start, End: start.Add(time.Millisecond),
+		Attributes: []Attribute{{Key: tracer.AttributeTag, Value: "health"}, {Key: tracer.AttributeResultCount, Value: 3}},
+		Failed:     true, Message: "unavailable",
+	}})
+	assert.NoError(t, err)
+	assert.Equal(t, "Bearer token", header)
+
+	assert.Len(t, received.ResourceSpans, 1)
+	assert.Equal(t, "article-dispatcher", *received.ResourceSpans[0].Resource.Attributes[0].Value.StringValue)
+	span := received.ResourceSpans[0].ScopeSpans[0].Spans[0]
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.TraceID)
+	assert.Equal(t, "53995c3f42cd8ad8", span.ParentSpanID)
+	assert.Equal(t, "1700000000000000000", span.StartTimeUnixNano)
+	assert.Equal(t, "1700000000001000000", span.EndTimeUnixNano)
+	assert.Equal(t, "health", *span.Attributes[0].Value.StringValue)
+	assert.Equal(t, "3", *span.Attributes[1].Value.IntValue)
+	assert.Equal(t, otlpStatus{Code: otlpStatusError, Message: "unavailable"}, span.Status)
+}
+
+func TestOTLPExporterRejected(t *testing.T) {
+	collector := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		writer.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer collector.Close()
+
+	err := OTLPExporter{Endpoint: collector.URL}.Export(context.Background(), []SpanData{{Name: "span"}})
+	assert.Error(t, err)
+}
+
+func TestStdoutExporter(t *testing.T) {
+	var out bytes.Buffer
+	start := time.Now()
+	err := StdoutExporter{Writer: &out}.Export(context.Background(), []SpanData{
+		{Name: "a", TraceID: "t", SpanID: "s", Start: start, End: start.Add(time.Second)},
+		{Name: "b", TraceID: "t", SpanID: "s2", ParentID: "s", Start: start, End: start,
+			Attributes: []Attribute{{Key: "k", Value: "v"}}},
+	})
+	assert.NoError(t, err)
+
+	lines := bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n"))
+	assert.Len(t, lines, 2)
+	var span stdoutSpan
+	assert.NoError(t, json.Unmarshal(lines[1], &span))
+	assert.Equal(t, "s", span.ParentID)
+	assert.Equal(t, map[string]interface{}{"k": "v"}, span.Attributes)
+	assert.Contains(t, string(lines[0]), `"duration":"1s"`)
+}
@@ -3,44 +3,125 @@ package services
 import (
 	"article-dispatcher/internal/domain/adaptors/logger"
 	"article-dispatcher/internal/domain/adaptors/repository"
+	"article-dispatcher/internal/domain/adaptors/tracer"
 	"article-dispatcher/internal/domain/models"
 	"article-dispatcher/internal/domain/services"
 	"context"
-	"fmt"
 )
 
 type ArticleService struct {
-	log  logger.Logger
-	repo repository.Repository
+	log    logger.Logger
+	repo   repository.Repository
+	tracer tracer.Tracer
 }
 
-func NewArticleService(l logger.Logger, repo repository.Repository) services.ArticleService {
+func NewArticleService(l logger.Logger, repo repository.Repository, t tracer.Tracer) services.ArticleService {
 	return &ArticleService{
-		log:  l,
-		repo: repo,
+		log:    l,
+		repo:   repo,
+		tracer: t,
 	}
 }
 
 func (as ArticleService) Create(ctx context.Context, article *models.Article) error {
+	ctx, span := as.tracer.Start(ctx, "ArticleService.Create")
+	defer span.End()
+
 	err := as.repo.Set(ctx, article)
 	if err != nil {
-		as.log.Error(fmt.Sprintf("article service, create article error due to %s", err))
+		logger.FromContext(ctx, as.log).Error("article service, create article error", logger.F("error", err))
+		span.RecordError(err)
+		return err
+	}
+	span.SetAttribute(tracer.AttributeArticleID, article.Id)
+	return nil
+}
+
+func (as ArticleService) Put(ctx context.Context, article *models.Article, precondition models.Precondition) (bool, error) {
+	ctx, span := as.tracer.Start(ctx, "ArticleService.Put")
+	defer span.End()
+	span.SetAttribute(tracer.AttributeArticleID, article.Id)
+
+	created, err := as.repo.Put(ctx, article, precondition)
+	if err != nil {
+		logger.FromContext(ctx, as.log).Error("article service, put article error", logger.F("error", err))
+		span.RecordError(err)
+		return created, err
 	}
-	return err
+	span.SetAttribute(tracer.AttributeCreated, created)
+	return created, nil
 }
 
 func (as ArticleService) Get(ctx context.Context, id string) (models.Article, error) {
+	ctx, span := as.tracer.Start(ctx, "ArticleService.Get")
+	defer span.End()
+	span.SetAttribute(tracer.AttributeArticleID, id)
+
 	article, err := as.repo.Get(ctx, id)
 	if err != nil {
-		as.log.Error(fmt.Sprintf("article service, get article error due to %s", err))
+		logger.FromContext(ctx, as.log).Error("article service, get article error", logger.F("error", err))
+		span.RecordError(err)
 	}
 	return article, err
 }
+
+func (as ArticleService) GetMany(ctx context.Context, ids []string) (models.BatchArticles, error) {
+	ctx, span := as.tracer.Start(ctx, "ArticleService.GetMany")
+	defer span.End()
+	span.SetAttribute(tracer.AttributeIDCount, len(ids))
+
+	articles, err := as.repo.GetMany(ctx, ids)
+	if err != nil {
+		logger.FromContext(ctx, as.log).Error("article service, get many articles error", logger.F("error", err))
+		span.RecordError(err)
+		return articles, err
+	}
+	span.SetAttribute(tracer.AttributeResultCount, len(articles.Articles))
+	return articles, nil
+}
+
 func (as ArticleService) Filter(ctx context.Context, tag string, date int) (models.TaggedArticles, error) {
+	ctx, span := as.tracer.Start(ctx, "ArticleService.Filter")
+	defer span.End()
+	span.SetAttribute(tracer.AttributeTag, tag)
+	span.SetAttribute(tracer.AttributeDate, date)
+
 	taggedArticles, err := as.repo.Filter(ctx, tag, date)
 	if err != nil {
-		as.log.Error(fmt.Sprintf("article service, filter articles error due to %s", err))
+		logger.FromContext(ctx, as.log).Error("article service, filter articles error", logger.F("error", err))
+		span.RecordError(err)
+		return taggedArticles, err
 	}
+	span.SetAttribute(tracer.AttributeResultCount, len(taggedArticles.Articles))
+	return taggedArticles, nil
+}
 
-	return taggedArticles, err
+func (as ArticleService) FilterExpanded(ctx context.Context, tag string, date int) (models.ExpandedTaggedArticles, error) {
+	ctx, span := as.tracer.Start(ctx, "ArticleService.FilterExpanded")
+	defer span.End()
+	span.SetAttribute(tracer.AttributeTag, tag)
+	span.SetAttribute(tracer.AttributeDate, date)
+
+	taggedArticles, err := as.repo.FilterExpanded(ctx, tag, date)
+	if err != nil {
+		logger.FromContext(ctx, as.log).Error("article service, filter expanded articles error", logger.F("error", err))
+		span.RecordError(err)
+		return taggedArticles, err
+	}
+	span.SetAttribute(tracer.AttributeResultCount, len(taggedArticles.Articles))
+	return taggedArticles, nil
+}
+
+func (as ArticleService) List(ctx context.Context, query models.Query) (models.ArticlePage, error) {
+	ctx, span := as.tracer.Start(ctx, "ArticleService.List")
+	defer span.End()
+
+	page, err := as.repo.List(ctx, query)
+	if err != nil {
+		logger.FromContext(ctx, as.log).Error("article service, list articles error", logger.F("error", err))
+		span.RecordError(err)
+		return page, err
+	}
+	span.SetAttribute(tracer.AttributeResultCount, len(page.Articles))
+	return page, nil
 }